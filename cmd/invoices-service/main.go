@@ -6,12 +6,17 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/fidesy-pay/invoices-service/internal/app"
+	"github.com/fidesy-pay/invoices-service/internal/app/admin"
 	"github.com/fidesy-pay/invoices-service/internal/config"
 	"github.com/fidesy-pay/invoices-service/internal/pkg/consumers"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/fx"
 	invoicesservice "github.com/fidesy-pay/invoices-service/internal/pkg/invoices-service"
 	"github.com/fidesy-pay/invoices-service/internal/pkg/storage"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/tokens"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/watcher"
 	crypto_service "github.com/fidesy-pay/invoices-service/pkg/crypto-service"
 	external_api "github.com/fidesy-pay/invoices-service/pkg/external-api"
 	"github.com/fidesy/sdk/common/grpc"
@@ -22,7 +27,10 @@ import (
 )
 
 const (
-	balancesTopic = "balances-json"
+	balancesTopic     = "balances-json"
+	transactionsTopic = "transactions-json"
+
+	webhookTimeout = 10 * time.Second
 )
 
 func main() {
@@ -74,11 +82,28 @@ func main() {
 		logger.Fatalf("postgres.Connect: %v", err)
 	}
 
-	storage := storage.New(pool)
+	watcherHub := watcher.New()
+
+	storage := storage.New(pool, watcherHub)
+
+	tokenConfigs := config.Get(config.Tokens).([]config.Token)
+	registryTokens := make([]tokens.Token, 0, len(tokenConfigs))
+	for _, token := range tokenConfigs {
+		registryTokens = append(registryTokens, tokens.Token{
+			Chain:     token.Chain,
+			Symbol:    token.Symbol,
+			Decimals:  token.Decimals,
+			Contract:  token.Contract,
+			MinAmount: token.MinAmount,
+			MaxAmount: token.MaxAmount,
+			Enabled:   token.Enabled,
+		})
+	}
+	tokenRegistry := tokens.NewRegistry(registryTokens)
 
 	err = kafka.RegisterConsumer(
 		ctx,
-		consumers.NewWalletBalanceConsumer(storage, cryptoServiceClient),
+		consumers.NewWalletBalanceConsumer(storage, externalAPI, tokenRegistry),
 		config.Get(config.KafkaBrokers).([]string),
 		balancesTopic,
 	)
@@ -86,6 +111,16 @@ func main() {
 		logger.Fatalf("consumers.RegisterConsumer: %v", err)
 	}
 
+	err = kafka.RegisterConsumer(
+		ctx,
+		consumers.NewTransactionConsumer(storage, externalAPI, tokenRegistry),
+		config.Get(config.KafkaBrokers).([]string),
+		transactionsTopic,
+	)
+	if err != nil {
+		logger.Fatalf("consumers.RegisterConsumer: %v", err)
+	}
+
 	// Register outbox
 
 	producer, err := kafka.NewProducer(ctx, config.Get(config.KafkaBrokers).([]string))
@@ -101,9 +136,45 @@ func main() {
 	)
 	go outboxProcessor.Publish(ctx)
 
-	invoicesService := invoicesservice.New(ctx, storage, cryptoServiceClient, externalAPI)
+	refundsOutboxProcessor := outbox_processor.New(
+		"refunds",
+		"refunds-json",
+		pool,
+		producer,
+	)
+	go refundsOutboxProcessor.Publish(ctx)
+
+	// fixed fx-rates go stale, they are used only if fx-rate-source is static
+	var fxRateSource invoicesservice.FXRateSource = fx.NewExternalAPIRateSource(externalAPI)
+	if config.Get(config.FXRateSource).(string) == "static" {
+		fxRateSource = fx.NewStaticRateSource(config.Get(config.FXRates).(map[string]float64))
+	}
+
+	invoicesService := invoicesservice.New(
+		ctx,
+		storage,
+		cryptoServiceClient,
+		externalAPI,
+		fxRateSource,
+		tokenRegistry,
+		watcherHub,
+		invoicesservice.NewWebhookHTTPClient(webhookTimeout),
+	)
 
 	impl := app.New(invoicesService)
+	adminImpl := admin.New(invoicesService)
+
+	operators := make(map[string]string)
+	for _, operator := range config.Get(config.AdminOperators).([]config.AdminOperator) {
+		operators[operator.Token] = operator.Name
+	}
+
+	// admin API is not served on public port, it has its own port with operator authentication
+	go func() {
+		if err := admin.Run(ctx, os.Getenv("ADMIN_GRPC_PORT"), adminImpl, operators); err != nil {
+			logger.Fatalf("admin.Run: %v", err)
+		}
+	}()
 
 	if err = server.Run(ctx, impl); err != nil {
 		logger.Fatalf("app.Run: %v", err)
@@ -0,0 +1,54 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type operatorKey struct{}
+
+// AuthInterceptor authenticates operator by token passed in authorization metadata
+// as "Bearer <token>", operators maps token to operator name
+func AuthInterceptor(operators map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		var token string
+		if values := md.Get("authorization"); len(values) > 0 {
+			token = strings.TrimPrefix(values[0], "Bearer ")
+		}
+
+		operator, ok := authenticate(operators, token)
+		if !ok {
+			return nil, status.Errorf(codes.Unauthenticated, "operator token is invalid")
+		}
+
+		return handler(context.WithValue(ctx, operatorKey{}, operator), req)
+	}
+}
+
+func authenticate(operators map[string]string, token string) (string, bool) {
+	if token == "" {
+		return "", false
+	}
+
+	for operatorToken, operator := range operators {
+		if subtle.ConstantTimeCompare([]byte(operatorToken), []byte(token)) == 1 {
+			return operator, true
+		}
+	}
+
+	return "", false
+}
+
+// OperatorFromContext returns name of operator authenticated by AuthInterceptor
+func OperatorFromContext(ctx context.Context) string {
+	operator, _ := ctx.Value(operatorKey{}).(string)
+	return operator
+}
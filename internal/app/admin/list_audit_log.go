@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *Implementation) ListAuditLog(ctx context.Context, req *desc.ListAuditLogRequest) (*desc.ListAuditLogResponse, error) {
+	err := validateListAuditLogRequest(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	entries, err := i.adminService.ListAuditLog(ctx, req)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "adminService.ListAuditLog: %v", err)
+	}
+
+	return &desc.ListAuditLogResponse{
+		Entries: models.AuditLogEntriesToProto(entries),
+	}, nil
+}
+
+func validateListAuditLogRequest(req *desc.ListAuditLogRequest) error {
+	if req.GetFilter() == nil {
+		return nil
+	}
+
+	err := validation.ValidateStruct(
+		req.Filter,
+		validation.Field(&req.Filter.InvoiceIdIn, validation.Each(is.UUIDv4)),
+	)
+
+	return err
+}
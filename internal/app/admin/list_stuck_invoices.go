@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *Implementation) ListStuckInvoices(ctx context.Context, req *desc.ListStuckInvoicesRequest) (*desc.ListStuckInvoicesResponse, error) {
+	err := validateListStuckInvoicesRequest(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	invoices, err := i.adminService.ListStuckInvoices(ctx, OperatorFromContext(ctx), req)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "adminService.ListStuckInvoices: %v", err)
+	}
+
+	return &desc.ListStuckInvoicesResponse{
+		Invoices: models.InvoicesToProto(invoices),
+	}, nil
+}
+
+func validateListStuckInvoicesRequest(req *desc.ListStuckInvoicesRequest) error {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.StatusIn, validation.Each(validation.In(
+			desc.InvoiceStatus_MANUAL_CONTROL,
+			desc.InvoiceStatus_SENDING_TO_CLIENT,
+		))),
+	)
+
+	return err
+}
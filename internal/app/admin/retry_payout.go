@@ -0,0 +1,36 @@
+package admin
+
+import (
+	"context"
+	"errors"
+
+	invoicesservice "github.com/fidesy-pay/invoices-service/internal/pkg/invoices-service"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *Implementation) RetryPayout(ctx context.Context, req *desc.RetryPayoutRequest) (*desc.RetryPayoutResponse, error) {
+	retryPayoutInput, err := invoicesservice.RetryPayoutInputFromRequest(req, OperatorFromContext(ctx))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	invoice, err := i.adminService.RetryPayout(ctx, retryPayoutInput)
+	if err != nil {
+		if errors.Is(err, invoicesservice.ErrInvoiceNotInManualControl) || errors.Is(err, models.ErrInvalidStatusTransition) {
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+
+		if errors.Is(err, models.ErrVersionConflict) {
+			return nil, status.Errorf(codes.Aborted, err.Error())
+		}
+
+		return nil, status.Errorf(codes.Internal, "adminService.RetryPayout: %v", err)
+	}
+
+	return &desc.RetryPayoutResponse{
+		Invoice: invoice.Proto(),
+	}, nil
+}
@@ -0,0 +1,38 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/fidesy/sdk/common/logger"
+	"google.golang.org/grpc"
+)
+
+// Run serves admin API on its own port with operator authentication. The port is not
+// registered in domain name service and must be reachable only from internal network
+func Run(ctx context.Context, port string, impl *Implementation, operators map[string]string) error {
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			AuthInterceptor(operators),
+		),
+	)
+	grpcServer.RegisterService(impl.GetDescription(), impl)
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
+	if err != nil {
+		return fmt.Errorf("net.Listen: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+	}()
+
+	logger.Info(fmt.Sprintf("admin grpcServer is running at %s port", port))
+	if err = grpcServer.Serve(lis); err != nil {
+		return fmt.Errorf("grpcServer.Serve: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"context"
+
+	invoicesservice "github.com/fidesy-pay/invoices-service/internal/pkg/invoices-service"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"google.golang.org/grpc"
+)
+
+type (
+	Implementation struct {
+		desc.UnimplementedInvoicesAdminServiceServer
+
+		adminService AdminService
+	}
+	AdminService interface {
+		RetryPayout(ctx context.Context, input *invoicesservice.RetryPayoutInput) (*models.Invoice, error)
+		MarkResolved(ctx context.Context, input *invoicesservice.MarkResolvedInput) (*models.Invoice, error)
+		ListStuckInvoices(ctx context.Context, operator string, req *desc.ListStuckInvoicesRequest) ([]*models.Invoice, error)
+		ListAuditLog(ctx context.Context, req *desc.ListAuditLogRequest) ([]*models.AuditLogEntry, error)
+	}
+)
+
+func New(adminService AdminService) *Implementation {
+	return &Implementation{
+		adminService: adminService,
+	}
+}
+
+func (i *Implementation) GetDescription() *grpc.ServiceDesc {
+	return &desc.InvoicesAdminService_ServiceDesc
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	invoicesservice "github.com/fidesy-pay/invoices-service/internal/pkg/invoices-service"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *Implementation) CancelInvoice(ctx context.Context, req *desc.CancelInvoiceRequest) (*desc.CancelInvoiceResponse, error) {
+	cancelInvoiceInput, err := invoicesservice.CancelInvoiceInputFromRequest(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	invoice, err := i.invoicesService.CancelInvoice(ctx, cancelInvoiceInput)
+	if err != nil {
+		if errors.Is(err, invoicesservice.ErrInvoiceNotCancellable) || errors.Is(err, models.ErrInvalidStatusTransition) {
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+
+		if errors.Is(err, models.ErrVersionConflict) {
+			return nil, status.Errorf(codes.Aborted, err.Error())
+		}
+
+		return nil, status.Errorf(codes.Internal, "invoicesService.CancelInvoice: %v", err)
+	}
+
+	return &desc.CancelInvoiceResponse{
+		Invoice: invoice.Proto(),
+	}, nil
+}
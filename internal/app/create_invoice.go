@@ -2,6 +2,9 @@ package app
 
 import (
 	"context"
+	"errors"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/fx"
 	invoicesservice "github.com/fidesy-pay/invoices-service/internal/pkg/invoices-service"
 	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
 	"google.golang.org/grpc/codes"
@@ -16,6 +19,14 @@ func (i *Implementation) CreateInvoice(ctx context.Context, req *desc.CreateInvo
 
 	invoice, err := i.invoicesService.CreateInvoice(ctx, createInvoiceInput)
 	if err != nil {
+		if errors.Is(err, invoicesservice.ErrIdempotencyKeyReused) {
+			return nil, status.Errorf(codes.AlreadyExists, err.Error())
+		}
+
+		if errors.Is(err, fx.ErrRateUnavailable) {
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+
 		return nil, status.Errorf(codes.Internal, "invoicesService.CreateInvoice: %v", err)
 	}
 
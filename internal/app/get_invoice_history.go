@@ -0,0 +1,37 @@
+package app
+
+import (
+	"context"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *Implementation) GetInvoiceHistory(ctx context.Context, req *desc.GetInvoiceHistoryRequest) (*desc.GetInvoiceHistoryResponse, error) {
+	err := validateGetInvoiceHistoryRequest(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	changes, err := i.invoicesService.GetInvoiceHistory(ctx, uuid.MustParse(req.GetId()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invoicesService.GetInvoiceHistory: %v", err)
+	}
+
+	return &desc.GetInvoiceHistoryResponse{
+		Changes: models.InvoiceStatusChangesToProto(changes),
+	}, nil
+}
+
+func validateGetInvoiceHistoryRequest(req *desc.GetInvoiceHistoryRequest) error {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.Id, validation.Required, is.UUIDv4))
+
+	return err
+}
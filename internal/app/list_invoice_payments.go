@@ -0,0 +1,37 @@
+package app
+
+import (
+	"context"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *Implementation) ListInvoicePayments(ctx context.Context, req *desc.ListInvoicePaymentsRequest) (*desc.ListInvoicePaymentsResponse, error) {
+	err := validateListInvoicePaymentsRequest(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	payments, err := i.invoicesService.ListInvoicePayments(ctx, uuid.MustParse(req.GetInvoiceId()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invoicesService.ListInvoicePayments: %v", err)
+	}
+
+	return &desc.ListInvoicePaymentsResponse{
+		Payments: models.InvoicePaymentsToProto(payments),
+	}, nil
+}
+
+func validateListInvoicePaymentsRequest(req *desc.ListInvoicePaymentsRequest) error {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.InvoiceId, validation.Required, is.UUIDv4))
+
+	return err
+}
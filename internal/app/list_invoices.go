@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	invoicesservice "github.com/fidesy-pay/invoices-service/internal/pkg/invoices-service"
 	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
 	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
 	validation "github.com/go-ozzo/ozzo-validation"
@@ -18,13 +19,19 @@ func (i *Implementation) ListInvoices(ctx context.Context, req *desc.ListInvoice
 		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
 	}
 
-	invoices, err := i.invoicesService.ListInvoices(ctx, req)
+	output, err := i.invoicesService.ListInvoices(ctx, req)
 	if err != nil {
+		if errors.Is(err, invoicesservice.ErrInvalidPageToken) {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+
 		return nil, status.Errorf(codes.Internal, "invoicesService.ListInvoices: %v", err)
 	}
 
 	return &desc.ListInvoicesResponse{
-		Invoices: models.InvoicesToProto(invoices),
+		Invoices:      models.InvoicesToProto(output.Invoices),
+		NextPageToken: output.NextPageToken,
+		TotalCount:    output.TotalCount,
 	}, nil
 }
 
@@ -37,6 +44,22 @@ func validateListInvoicesRequest(req *desc.ListInvoicesRequest) error {
 	err := validation.ValidateStruct(
 		filter,
 		validation.Field(&filter.ClientIdIn, validation.Each(validation.NotNil, is.UUIDv4)),
+		validation.Field(&filter.PayerClientIdIn, validation.Each(validation.NotNil, is.UUIDv4)),
+		validation.Field(&filter.UsdAmountFrom, validation.Min(0.0)),
+		validation.Field(&filter.UsdAmountTo, validation.Min(0.0)),
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if filter.UsdAmountFrom != nil && filter.UsdAmountTo != nil && filter.GetUsdAmountFrom() > filter.GetUsdAmountTo() {
+		return errors.New("usd_amount_from must not be greater than usd_amount_to")
+	}
+
+	if filter.CreatedAtFrom != nil && filter.CreatedAtTo != nil &&
+		filter.GetCreatedAtFrom().AsTime().After(filter.GetCreatedAtTo().AsTime()) {
+		return errors.New("created_at_from must not be after created_at_to")
+	}
+
+	return nil
 }
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"context"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *Implementation) ListPaymentMethods(ctx context.Context, req *desc.ListPaymentMethodsRequest) (*desc.ListPaymentMethodsResponse, error) {
+	err := validateListPaymentMethodsRequest(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	methods := i.invoicesService.ListPaymentMethods(ctx, req.Chain)
+
+	return &desc.ListPaymentMethodsResponse{
+		PaymentMethods: models.PaymentMethodsToProto(methods),
+	}, nil
+}
+
+func validateListPaymentMethodsRequest(req *desc.ListPaymentMethodsRequest) error {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.Chain, validation.NilOrNotEmpty))
+
+	return err
+}
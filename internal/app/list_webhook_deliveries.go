@@ -0,0 +1,43 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *Implementation) ListWebhookDeliveries(ctx context.Context, req *desc.ListWebhookDeliveriesRequest) (*desc.ListWebhookDeliveriesResponse, error) {
+	err := validateListWebhookDeliveriesRequest(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	deliveries, err := i.invoicesService.ListWebhookDeliveries(ctx, req)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invoicesService.ListWebhookDeliveries: %v", err)
+	}
+
+	return &desc.ListWebhookDeliveriesResponse{
+		Deliveries: models.WebhookDeliveriesToProto(deliveries),
+	}, nil
+}
+
+func validateListWebhookDeliveriesRequest(req *desc.ListWebhookDeliveriesRequest) error {
+	if req == nil || req.Filter == nil {
+		return errors.New("filter is required")
+	}
+
+	filter := req.GetFilter()
+	err := validation.ValidateStruct(
+		filter,
+		validation.Field(&filter.ClientIdIn, validation.Each(validation.NotNil, is.UUIDv4)),
+		validation.Field(&filter.InvoiceIdIn, validation.Each(validation.NotNil, is.UUIDv4)),
+	)
+	return err
+}
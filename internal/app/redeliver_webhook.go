@@ -0,0 +1,36 @@
+package app
+
+import (
+	"context"
+
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *Implementation) RedeliverWebhook(ctx context.Context, req *desc.RedeliverWebhookRequest) (*desc.RedeliverWebhookResponse, error) {
+	err := validateRedeliverWebhookRequest(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	delivery, err := i.invoicesService.RedeliverWebhook(ctx, uuid.MustParse(req.GetId()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invoicesService.RedeliverWebhook: %v", err)
+	}
+
+	return &desc.RedeliverWebhookResponse{
+		Delivery: delivery.Proto(),
+	}, nil
+}
+
+func validateRedeliverWebhookRequest(req *desc.RedeliverWebhookRequest) error {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.Id, validation.Required, is.UUIDv4))
+
+	return err
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/fx"
+	invoicesservice "github.com/fidesy-pay/invoices-service/internal/pkg/invoices-service"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *Implementation) RefreshQuote(ctx context.Context, req *desc.RefreshQuoteRequest) (*desc.RefreshQuoteResponse, error) {
+	err := validateRefreshQuoteRequest(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	invoice, err := i.invoicesService.RefreshQuote(ctx, uuid.MustParse(req.GetId()), req.Version)
+	if err != nil {
+		if errors.Is(err, invoicesservice.ErrInvoiceNotQuotable) ||
+			errors.Is(err, invoicesservice.ErrTokenNotSupported) ||
+			errors.Is(err, invoicesservice.ErrTokenAmountBelowMin) ||
+			errors.Is(err, invoicesservice.ErrTokenAmountAboveMax) ||
+			errors.Is(err, models.ErrInvalidStatusTransition) ||
+			errors.Is(err, fx.ErrRateUnavailable) {
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+
+		if errors.Is(err, models.ErrVersionConflict) {
+			return nil, status.Errorf(codes.Aborted, err.Error())
+		}
+
+		return nil, status.Errorf(codes.Internal, "invoicesService.RefreshQuote: %v", err)
+	}
+
+	return &desc.RefreshQuoteResponse{
+		Invoice: invoice.Proto(),
+	}, nil
+}
+
+func validateRefreshQuoteRequest(req *desc.RefreshQuoteRequest) error {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.Id, validation.Required, is.UUIDv4))
+
+	return err
+}
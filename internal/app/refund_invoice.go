@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	invoicesservice "github.com/fidesy-pay/invoices-service/internal/pkg/invoices-service"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *Implementation) RefundInvoice(ctx context.Context, req *desc.RefundInvoiceRequest) (*desc.RefundInvoiceResponse, error) {
+	refundInvoiceInput, err := invoicesservice.RefundInvoiceInputFromRequest(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	refund, err := i.invoicesService.RefundInvoice(ctx, refundInvoiceInput)
+	if err != nil {
+		switch {
+		case errors.Is(err, invoicesservice.ErrInvoiceNotRefundable),
+			errors.Is(err, invoicesservice.ErrRefundTokenNotSupported),
+			errors.Is(err, invoicesservice.ErrTokenNotSupported):
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		case errors.Is(err, models.ErrRefundAmountExceeded):
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		case errors.Is(err, models.ErrVersionConflict):
+			return nil, status.Errorf(codes.Aborted, err.Error())
+		}
+
+		return nil, status.Errorf(codes.Internal, "invoicesService.RefundInvoice: %v", err)
+	}
+
+	return &desc.RefundInvoiceResponse{
+		Refund: refund.Proto(),
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 	invoicesservice "github.com/fidesy-pay/invoices-service/internal/pkg/invoices-service"
 	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
 	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 )
 
@@ -19,7 +20,17 @@ type (
 		CreateInvoice(ctx context.Context, input *invoicesservice.CreateInvoiceInput) (*models.Invoice, error)
 		CheckInvoice(ctx context.Context, invoiceID string) (*models.Invoice, error)
 		UpdateInvoice(ctx context.Context, input *invoicesservice.UpdateInvoiceInput) (*models.Invoice, error)
-		ListInvoices(ctx context.Context, req *desc.ListInvoicesRequest) ([]*models.Invoice, error)
+		ListInvoices(ctx context.Context, req *desc.ListInvoicesRequest) (*invoicesservice.ListInvoicesOutput, error)
+		CancelInvoice(ctx context.Context, input *invoicesservice.CancelInvoiceInput) (*models.Invoice, error)
+		RefundInvoice(ctx context.Context, input *invoicesservice.RefundInvoiceInput) (*models.Refund, error)
+		RefreshQuote(ctx context.Context, invoiceID uuid.UUID, version *int64) (*models.Invoice, error)
+		WatchInvoice(ctx context.Context, invoiceID uuid.UUID, send func(invoice *models.Invoice) error) error
+		SetWebhook(ctx context.Context, input *invoicesservice.SetWebhookInput) (string, error)
+		ListWebhookDeliveries(ctx context.Context, req *desc.ListWebhookDeliveriesRequest) ([]*models.WebhookDelivery, error)
+		RedeliverWebhook(ctx context.Context, deliveryID uuid.UUID) (*models.WebhookDelivery, error)
+		GetInvoiceHistory(ctx context.Context, invoiceID uuid.UUID) ([]*models.InvoiceStatusChange, error)
+		ListInvoicePayments(ctx context.Context, invoiceID uuid.UUID) ([]*models.InvoicePayment, error)
+		ListPaymentMethods(ctx context.Context, chain *string) []*models.PaymentMethod
 	}
 )
 
@@ -0,0 +1,26 @@
+package app
+
+import (
+	"context"
+
+	invoicesservice "github.com/fidesy-pay/invoices-service/internal/pkg/invoices-service"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *Implementation) SetWebhook(ctx context.Context, req *desc.SetWebhookRequest) (*desc.SetWebhookResponse, error) {
+	setWebhookInput, err := invoicesservice.SetWebhookInputFromRequest(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	secret, err := i.invoicesService.SetWebhook(ctx, setWebhookInput)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invoicesService.SetWebhook: %v", err)
+	}
+
+	return &desc.SetWebhookResponse{
+		Secret: secret,
+	}, nil
+}
@@ -3,7 +3,9 @@ package app
 import (
 	"context"
 	"errors"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/fx"
 	invoicesservice "github.com/fidesy-pay/invoices-service/internal/pkg/invoices-service"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
 	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -17,10 +19,21 @@ func (i *Implementation) UpdateInvoice(ctx context.Context, req *desc.UpdateInvo
 
 	invoice, err := i.invoicesService.UpdateInvoice(ctx, updateInvoiceInput)
 	if err != nil {
-		if errors.Is(err, invoicesservice.ErrInvoiceAlreadyCompleted) {
+		if errors.Is(err, models.ErrInvalidStatusTransition) ||
+			errors.Is(err, fx.ErrRateUnavailable) {
+			return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+		}
+
+		if errors.Is(err, invoicesservice.ErrTokenNotSupported) ||
+			errors.Is(err, invoicesservice.ErrTokenAmountBelowMin) ||
+			errors.Is(err, invoicesservice.ErrTokenAmountAboveMax) {
 			return nil, status.Errorf(codes.InvalidArgument, err.Error())
 		}
 
+		if errors.Is(err, models.ErrVersionConflict) {
+			return nil, status.Errorf(codes.Aborted, err.Error())
+		}
+
 		return nil, status.Errorf(codes.Internal, "invoicesService.UpdateInvoice: %v", err)
 	}
 
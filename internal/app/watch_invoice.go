@@ -0,0 +1,37 @@
+package app
+
+import (
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (i *Implementation) WatchInvoice(req *desc.WatchInvoiceRequest, stream desc.InvoicesService_WatchInvoiceServer) error {
+	err := validateWatchInvoiceRequest(req)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "validation failed: %v", err)
+	}
+
+	err = i.invoicesService.WatchInvoice(stream.Context(), uuid.MustParse(req.GetId()), func(invoice *models.Invoice) error {
+		return stream.Send(&desc.WatchInvoiceResponse{
+			Invoice: invoice.Proto(),
+		})
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "invoicesService.WatchInvoice: %v", err)
+	}
+
+	return nil
+}
+
+func validateWatchInvoiceRequest(req *desc.WatchInvoiceRequest) error {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.Id, validation.Required, is.UUIDv4))
+
+	return err
+}
@@ -10,17 +10,52 @@ import (
 )
 
 const (
-	KafkaBrokers   = "kafka-brokers"
-	PgDsn          = "pg-dsn"
-	ExpireInterval = "expire-interval"
+	KafkaBrokers          = "kafka-brokers"
+	PgDsn                 = "pg-dsn"
+	ExpireInterval        = "expire-interval"
+	PaymentTolerance      = "payment-tolerance"
+	FXRates               = "fx-rates"
+	FXRateSource          = "fx-rate-source"
+	QuoteTTL              = "quote-ttl"
+	PayoutMaxFeeRatio     = "payout-max-fee-ratio"
+	Tokens                = "tokens"
+	ExpireWorkerInterval  = "expire-worker-interval"
+	ExpireWorkerBatchSize = "expire-worker-batch-size"
+	AdminOperators        = "admin-operators"
 )
 
 var conf *Config
 
 type Config struct {
-	KafkaBrokers   string        `yaml:"kafka-brokers"`
-	PgDsn          string        `yaml:"pg-dsn"`
-	ExpireInterval time.Duration `yaml:"expire-interval"`
+	KafkaBrokers          string             `yaml:"kafka-brokers"`
+	PgDsn                 string             `yaml:"pg-dsn"`
+	ExpireInterval        time.Duration      `yaml:"expire-interval"`
+	PaymentTolerance      float64            `yaml:"payment-tolerance"`
+	FXRates               map[string]float64 `yaml:"fx-rates"`
+	FXRateSource          string             `yaml:"fx-rate-source"`
+	QuoteTTL              time.Duration      `yaml:"quote-ttl"`
+	PayoutMaxFeeRatio     float64            `yaml:"payout-max-fee-ratio"`
+	Tokens                []Token            `yaml:"tokens"`
+	ExpireWorkerInterval  time.Duration      `yaml:"expire-worker-interval"`
+	ExpireWorkerBatchSize uint64             `yaml:"expire-worker-batch-size"`
+	AdminOperators        []AdminOperator    `yaml:"admin-operators"`
+}
+
+// Token is entry of supported tokens registry
+type Token struct {
+	Chain     string  `yaml:"chain"`
+	Symbol    string  `yaml:"symbol"`
+	Decimals  int     `yaml:"decimals"`
+	Contract  string  `yaml:"contract"`
+	MinAmount float64 `yaml:"min-amount"`
+	MaxAmount float64 `yaml:"max-amount"`
+	Enabled   bool    `yaml:"enabled"`
+}
+
+// AdminOperator is operator allowed to call admin API with token
+type AdminOperator struct {
+	Name  string `yaml:"name"`
+	Token string `yaml:"token"`
 }
 
 func Init() error {
@@ -43,6 +78,24 @@ func Get(key string) interface{} {
 		return conf.PgDsn
 	case ExpireInterval:
 		return conf.ExpireInterval
+	case PaymentTolerance:
+		return conf.PaymentTolerance
+	case FXRates:
+		return conf.FXRates
+	case FXRateSource:
+		return conf.FXRateSource
+	case QuoteTTL:
+		return conf.QuoteTTL
+	case PayoutMaxFeeRatio:
+		return conf.PayoutMaxFeeRatio
+	case Tokens:
+		return conf.Tokens
+	case ExpireWorkerInterval:
+		return conf.ExpireWorkerInterval
+	case ExpireWorkerBatchSize:
+		return conf.ExpireWorkerBatchSize
+	case AdminOperators:
+		return conf.AdminOperators
 	default:
 		panic(ErrConfigNotFoundByKey(key))
 	}
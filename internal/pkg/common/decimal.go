@@ -0,0 +1,37 @@
+package common
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// FloatToRat returns exact value of shortest decimal representation of value,
+// e.g. 0.1 is 1/10 and not the nearest binary fraction
+func FloatToRat(value float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(value, 'f', -1, 64))
+	return r
+}
+
+// CeilRat rounds value up to integer
+func CeilRat(value *big.Rat) *big.Int {
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if remainder.Sign() > 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+
+	return quotient
+}
+
+// RoundRat rounds value to the nearest integer, halves are rounded away from zero
+func RoundRat(value *big.Rat) *big.Int {
+	doubled := new(big.Int).Mul(value.Num(), big.NewInt(2))
+	half := new(big.Int).Quo(doubled, value.Denom())
+	half.Add(half, big.NewInt(int64(half.Sign())))
+
+	return half.Quo(half, big.NewInt(2))
+}
+
+// Pow10 returns 10^exp
+func Pow10(exp int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+}
@@ -0,0 +1,254 @@
+package consumers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/fidesy-pay/invoices-service/internal/config"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/common"
+	invoicesservice "github.com/fidesy-pay/invoices-service/internal/pkg/invoices-service"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/storage"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/tokens"
+	external_api "github.com/fidesy-pay/invoices-service/pkg/external-api"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/fidesy/sdk/common/logger"
+	"github.com/fidesy/sdk/common/postgres"
+	"github.com/samber/lo"
+)
+
+const maxConflictAttempts = 3
+
+// payments moves invoice through payment statuses by amount received on its address
+type payments struct {
+	storage       Storage
+	externalAPI   ExternalAPI
+	tokenRegistry TokenRegistry
+}
+
+// retryOnConflict runs fn again if invoice is changed concurrently,
+// fn reads invoice again and applies payment to its new state
+func retryOnConflict(fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if !errors.Is(err, models.ErrVersionConflict) || attempt == maxConflictAttempts {
+			return err
+		}
+	}
+}
+
+func (p *payments) getInvoiceByAddress(ctx context.Context, address string) (*models.Invoice, error) {
+	invoices, err := p.storage.ListInvoices(ctx, storage.ListInvoicesFilter{
+		AddressIn: []string{strings.ToLower(address)},
+	}, postgres.NewPagination(1, 100))
+	if err != nil {
+		return nil, fmt.Errorf("storage.ListInvoices: %v", err)
+	}
+
+	if len(invoices) == 0 {
+		return nil, invoicesservice.ErrInvoiceNotFoundByAddress(address)
+	}
+
+	return invoices[0], nil
+}
+
+func (p *payments) getToken(invoice *models.Invoice) (tokens.Token, error) {
+	token, ok := p.tokenRegistry.Get(invoice.Chain, invoice.Token)
+	if !ok {
+		return tokens.Token{}, fmt.Errorf("%w: chain = %q, token = %q", invoicesservice.ErrTokenNotSupported, invoice.Chain, invoice.Token)
+	}
+
+	return token, nil
+}
+
+// receivedUnits returns total amount of payments in base units of token
+func receivedUnits(invoicePayments []*models.InvoicePayment, token tokens.Token) *big.Int {
+	total := new(big.Int)
+	for _, payment := range invoicePayments {
+		// payments recorded before exact amounts
+		if payment.AmountUnits == nil {
+			total.Add(total, token.ToBaseUnits(payment.Amount))
+			continue
+		}
+
+		total.Add(total, payment.AmountUnits.Int())
+	}
+
+	return total
+}
+
+// tokenAmountUnits returns amount of token invoice is quoted in, in base units of token
+func tokenAmountUnits(invoice *models.Invoice, token tokens.Token) *big.Int {
+	// invoices quoted before exact amounts
+	if invoice.TokenAmountUnits == nil {
+		return token.ToBaseUnits(lo.FromPtr(invoice.TokenAmount))
+	}
+
+	return invoice.TokenAmountUnits.Int()
+}
+
+// apply updates invoice with total amount of token received on its address,
+// messageKey is recorded as processed with the update if it is set
+func (p *payments) apply(ctx context.Context, invoice *models.Invoice, token tokens.Token, received *big.Int, messageKey string) error {
+	// merchant voided the invoice or it expired, funds must stay on the invoice wallet
+	// and must never be forwarded to client
+	if invoice.Status == desc.InvoiceStatus_CANCELLED || invoice.Status == desc.InvoiceStatus_EXPIRED {
+		return p.handleClosedInvoicePayment(ctx, invoice, token, received, messageKey)
+	}
+
+	// invoice which is already paid is never paid out again
+	if invoice.Status != desc.InvoiceStatus_PENDING && invoice.Status != desc.InvoiceStatus_PARTIALLY_PAID {
+		return nil
+	}
+
+	if invoice.QuoteExpired(time.Now()) {
+		err := p.requote(ctx, invoice, token)
+		if err != nil {
+			return fmt.Errorf("requote: %w", err)
+		}
+	}
+
+	var (
+		required  = tokenAmountUnits(invoice, token)
+		tolerance = common.FloatToRat(config.Get(config.PaymentTolerance).(float64))
+		one       = big.NewRat(1, 1)
+	)
+
+	// received < required * (1 - tolerance)
+	minRequired := new(big.Rat).Mul(new(big.Rat).SetInt(required), new(big.Rat).Sub(one, tolerance))
+	if new(big.Rat).SetInt(received).Cmp(minRequired) < 0 {
+		return p.handlePartialPayment(ctx, invoice, token, received, messageKey)
+	}
+
+	invoice.ReceivedAmountUnits = models.NewUnits(received)
+	invoice.ReceivedAmount = lo.ToPtr(token.FromBaseUnits(received))
+
+	// received > required * (1 + tolerance)
+	maxRequired := new(big.Rat).Mul(new(big.Rat).SetInt(required), new(big.Rat).Add(one, tolerance))
+	if new(big.Rat).SetInt(received).Cmp(maxRequired) > 0 {
+		// surplus is forwarded to client with the rest of the funds,
+		// it is recorded to be refunded to payer or credited later
+		overpaid := new(big.Int).Sub(received, required)
+		invoice.OverpaidAmountUnits = models.NewUnits(overpaid)
+		invoice.OverpaidAmount = lo.ToPtr(token.FromBaseUnits(overpaid))
+	}
+
+	// funds are forwarded to client by transfer worker of invoices service,
+	// so that every payout is sent with the same gas strategy. Conditional
+	// status change claims the payout: only one of concurrent or redelivered
+	// messages moves invoice to SENDING_TO_CLIENT, with the message recorded
+	// as processed in the same transaction
+	invoice.Status = desc.InvoiceStatus_SENDING_TO_CLIENT
+	_, err := p.storage.UpdateInvoice(ctx, invoice, storage.InvoiceUpdate{
+		Reason:              fmt.Sprintf("received %s of %s %s", token.Format(received), token.Format(required), invoice.Token),
+		ProcessedMessageKey: messageKey,
+	})
+	if err != nil {
+		// invoice is expired or cancelled concurrently, funds must not be forwarded
+		if errors.Is(err, models.ErrInvalidStatusTransition) {
+			logger.Info(fmt.Sprintf("payment of invoice is not accepted: %v", err))
+			return nil
+		}
+
+		return fmt.Errorf("storage.UpdateInvoice: %w", err)
+	}
+
+	return nil
+}
+
+// requote re-prices invoice which quote is expired before payment is accepted.
+// Token amount is only raised: if token price dropped payer has to send the difference
+// and invoice becomes PARTIALLY_PAID, if price rose the original quote is honoured
+func (p *payments) requote(ctx context.Context, invoice *models.Invoice, token tokens.Token) error {
+	if invoice.PriceUsd == nil {
+		return nil
+	}
+
+	tokenPriceResp, err := p.externalAPI.GetPrice(ctx, &external_api.GetPriceRequest{
+		Symbol: invoice.Token,
+	})
+	if err != nil {
+		return fmt.Errorf("externalAPI.GetPrice: %w", err)
+	}
+
+	if tokenPriceResp.GetPriceUsd() <= 0 {
+		return fmt.Errorf("invalid price of %s: %v", invoice.Token, tokenPriceResp.GetPriceUsd())
+	}
+
+	required := tokenAmountUnits(invoice, token)
+	usdAmount := new(big.Rat).Mul(
+		new(big.Rat).SetFrac(required, common.Pow10(token.Decimals)),
+		common.FloatToRat(*invoice.PriceUsd),
+	)
+
+	tokenUnits := token.Quote(usdAmount, tokenPriceResp.GetPriceUsd())
+	if tokenUnits.Cmp(required) > 0 {
+		invoice.TokenAmountUnits = models.NewUnits(tokenUnits)
+		invoice.TokenDecimals = lo.ToPtr(int32(token.Decimals))
+		invoice.TokenAmount = lo.ToPtr(token.FromBaseUnits(tokenUnits))
+		invoice.PriceUsd = lo.ToPtr(tokenPriceResp.GetPriceUsd())
+	}
+
+	invoice.QuoteExpiresAt = lo.ToPtr(time.Now().Add(config.Get(config.QuoteTTL).(time.Duration)))
+
+	return nil
+}
+
+// handlePartialPayment records received amount, every change produces invoice event through outbox
+func (p *payments) handlePartialPayment(ctx context.Context, invoice *models.Invoice, token tokens.Token, received *big.Int, messageKey string) error {
+	if received.Sign() <= 0 {
+		return nil
+	}
+
+	if invoice.ReceivedAmountUnits != nil && invoice.ReceivedAmountUnits.Int().Cmp(received) == 0 {
+		return nil
+	}
+
+	invoice.Status = desc.InvoiceStatus_PARTIALLY_PAID
+	invoice.ReceivedAmountUnits = models.NewUnits(received)
+	invoice.ReceivedAmount = lo.ToPtr(token.FromBaseUnits(received))
+	_, err := p.storage.UpdateInvoice(ctx, invoice, storage.InvoiceUpdate{
+		Reason: fmt.Sprintf(
+			"received %s of %s %s",
+			token.Format(received), token.Format(tokenAmountUnits(invoice, token)), invoice.Token,
+		),
+		ProcessedMessageKey: messageKey,
+	})
+	if err != nil {
+		return fmt.Errorf("storage.UpdateInvoice: %w", err)
+	}
+
+	return nil
+}
+
+// handleClosedInvoicePayment records amount received on cancelled or expired invoice without
+// changing its status, so that funds can be refunded to payer
+func (p *payments) handleClosedInvoicePayment(ctx context.Context, invoice *models.Invoice, token tokens.Token, received *big.Int, messageKey string) error {
+	if received.Sign() <= 0 {
+		return nil
+	}
+
+	if invoice.ReceivedAmountUnits != nil && invoice.ReceivedAmountUnits.Int().Cmp(received) == 0 {
+		return nil
+	}
+
+	invoice.ReceivedAmountUnits = models.NewUnits(received)
+	invoice.ReceivedAmount = lo.ToPtr(token.FromBaseUnits(received))
+	_, err := p.storage.UpdateInvoice(ctx, invoice, storage.InvoiceUpdate{
+		Reason: fmt.Sprintf(
+			"received %s %s on %s invoice",
+			token.Format(received), invoice.Token, strings.ToLower(invoice.Status.String()),
+		),
+		ProcessedMessageKey: messageKey,
+		RecordStatus:        true,
+	})
+	if err != nil {
+		return fmt.Errorf("storage.UpdateInvoice: %w", err)
+	}
+
+	return nil
+}
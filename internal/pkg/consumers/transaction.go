@@ -0,0 +1,99 @@
+package consumers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	"github.com/fidesy/sdk/common/postgres"
+	"github.com/google/uuid"
+)
+
+// TransactionConsumer records transactions sent to invoice addresses
+// and applies their total to invoice
+type TransactionConsumer struct {
+	payments
+}
+
+func NewTransactionConsumer(
+	storage Storage,
+	externalAPI ExternalAPI,
+	tokenRegistry TokenRegistry,
+) *TransactionConsumer {
+	return &TransactionConsumer{
+		payments: payments{
+			storage:       storage,
+			externalAPI:   externalAPI,
+			tokenRegistry: tokenRegistry,
+		},
+	}
+}
+
+func (c *TransactionConsumer) Consume(ctx context.Context, msg []byte) error {
+	transaction := new(models.Transaction)
+	err := json.Unmarshal(msg, &transaction)
+	if err != nil {
+		return fmt.Errorf("json.Unmarshal: %v", err)
+	}
+
+	ctx = models.WithActor(ctx, models.ActorConsumer)
+
+	invoice, err := c.getInvoiceByAddress(ctx, transaction.Receiver)
+	if err != nil {
+		return err
+	}
+
+	if transaction.Chain != invoice.Chain || transaction.Token != invoice.Token {
+		return nil
+	}
+
+	token, err := c.getToken(invoice)
+	if err != nil {
+		return err
+	}
+
+	amountUnits := transaction.AmountUnits
+	if amountUnits == nil {
+		amountUnits = models.NewUnits(token.ToBaseUnits(transaction.Amount))
+	}
+
+	// payment is recorded whatever invoice status is, so that it can be refunded
+	_, err = c.storage.CreateInvoicePayment(ctx, &models.InvoicePayment{
+		InvoiceID:       invoice.ID,
+		TransactionHash: transaction.Hash,
+		Sender:          transaction.Sender,
+		Amount:          token.FromBaseUnits(amountUnits.Int()),
+		AmountUnits:     amountUnits,
+		Chain:           transaction.Chain,
+		Token:           transaction.Token,
+	})
+	if err != nil && !errors.Is(err, postgres.ErrAlreadyExists) {
+		return fmt.Errorf("storage.CreateInvoicePayment: %w", err)
+	}
+
+	return retryOnConflict(func() error {
+		return c.applyPayments(ctx, transaction.Receiver)
+	})
+}
+
+func (c *TransactionConsumer) applyPayments(ctx context.Context, address string) error {
+	invoice, err := c.getInvoiceByAddress(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	token, err := c.getToken(invoice)
+	if err != nil {
+		return err
+	}
+
+	invoicePayments, err := c.storage.ListInvoicePayments(ctx, []uuid.UUID{invoice.ID})
+	if err != nil {
+		return fmt.Errorf("storage.ListInvoicePayments: %w", err)
+	}
+
+	// transactions are deduplicated by hash of recorded payment
+	return c.apply(ctx, invoice, token, receivedUnits(invoicePayments, token), "")
+}
@@ -6,39 +6,49 @@ import (
 	"fmt"
 	"strings"
 
-	invoicesservice "github.com/fidesy-pay/invoices-service/internal/pkg/invoices-service"
 	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
 	"github.com/fidesy-pay/invoices-service/internal/pkg/storage"
-	crypto_service "github.com/fidesy-pay/invoices-service/pkg/crypto-service"
-	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/tokens"
+	external_api "github.com/fidesy-pay/invoices-service/pkg/external-api"
 	"github.com/fidesy/sdk/common/postgres"
-	"github.com/samber/lo"
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 )
 
 type (
 	WalletBalanceConsumer struct {
-		storage             Storage
-		cryptoServiceClient CryptoServiceClient
+		payments
 	}
 
 	Storage interface {
 		ListInvoices(ctx context.Context, filter storage.ListInvoicesFilter, pagination postgres.Pagination) ([]*models.Invoice, error)
-		UpdateInvoice(ctx context.Context, invoice *models.Invoice) (*models.Invoice, error)
+		UpdateInvoice(ctx context.Context, invoice *models.Invoice, update storage.InvoiceUpdate) (*models.Invoice, error)
+		ListInvoicePayments(ctx context.Context, invoiceIDs []uuid.UUID) ([]*models.InvoicePayment, error)
+		CreateInvoicePayment(ctx context.Context, payment *models.InvoicePayment) (*models.InvoicePayment, error)
+		IsMessageProcessed(ctx context.Context, key string) (bool, error)
+		MarkMessageProcessed(ctx context.Context, key string) error
 	}
 
-	CryptoServiceClient interface {
-		Transfer(ctx context.Context, in *crypto_service.TransferRequest, opts ...grpc.CallOption) (*crypto_service.TransferResponse, error)
+	TokenRegistry interface {
+		Get(chain, symbol string) (tokens.Token, bool)
+	}
+
+	ExternalAPI interface {
+		GetPrice(ctx context.Context, in *external_api.GetPriceRequest, opts ...grpc.CallOption) (*external_api.GetPriceResponse, error)
 	}
 )
 
 func NewWalletBalanceConsumer(
 	storage Storage,
-	cryptoServiceClient CryptoServiceClient,
+	externalAPI ExternalAPI,
+	tokenRegistry TokenRegistry,
 ) *WalletBalanceConsumer {
 	return &WalletBalanceConsumer{
-		storage:             storage,
-		cryptoServiceClient: cryptoServiceClient,
+		payments: payments{
+			storage:       storage,
+			externalAPI:   externalAPI,
+			tokenRegistry: tokenRegistry,
+		},
 	}
 }
 
@@ -49,45 +59,75 @@ func (c *WalletBalanceConsumer) Consume(ctx context.Context, msg []byte) error {
 		return fmt.Errorf("json.Unmarshal: %v", err)
 	}
 
-	invoices, err := c.storage.ListInvoices(ctx, storage.ListInvoicesFilter{
-		AddressIn: []string{strings.ToLower(wallet.Address)},
-	}, postgres.NewPagination(1, 100))
-	if err != nil {
-		return fmt.Errorf("storage.ListInvoices: %v", err)
+	if wallet.Balance == nil {
+		return fmt.Errorf("balance of wallet %s is missing", wallet.Address)
 	}
 
-	if len(invoices) == 0 {
-		return invoicesservice.ErrInvoiceNotFoundByAddress(wallet.Address)
+	ctx = models.WithActor(ctx, models.ActorConsumer)
+
+	// redelivered message is skipped, so that it never changes invoice again
+	key := balanceMessageKey(wallet)
+	processed, err := c.storage.IsMessageProcessed(ctx, key)
+	if err != nil {
+		return fmt.Errorf("storage.IsMessageProcessed: %w", err)
 	}
 
-	invoice := invoices[0]
-	if wallet.Chain != invoice.Chain || wallet.Token != invoice.Token {
+	if processed {
 		return nil
 	}
 
-	if wallet.Balance < int64(*invoice.TokenAmount*1e18) {
-		return nil
+	err = retryOnConflict(func() error {
+		return c.applyBalance(ctx, wallet, key)
+	})
+	if err != nil {
+		return err
 	}
 
-	invoice.Status = desc.InvoiceStatus_SENDING_TO_CLIENT
-	_, err = c.storage.UpdateInvoice(ctx, invoice)
+	// message which did not change invoice is recorded separately
+	err = c.storage.MarkMessageProcessed(ctx, key)
 	if err != nil {
-		return fmt.Errorf("storage.UpdateInvoice: %v", err)
+		return fmt.Errorf("storage.MarkMessageProcessed: %w", err)
 	}
 
-	_, err = c.cryptoServiceClient.Transfer(ctx, &crypto_service.TransferRequest{
-		ClientId:  invoice.ClientID.String(),
-		InvoiceId: lo.ToPtr(invoice.ID.String()),
-	})
+	return nil
+}
+
+// balanceMessageKey identifies balance message by its content, balance messages have no id
+// and the same balance of wallet is applied once. Balance published again after wallet is
+// swept is skipped too, which is safe as paid invoice is never paid out again
+func balanceMessageKey(wallet *models.WalletMessage) string {
+	return fmt.Sprintf(
+		"balances:%s:%s:%s:%s",
+		strings.ToLower(wallet.Address), wallet.Chain, wallet.Token, wallet.Balance.String(),
+	)
+}
+
+func (c *WalletBalanceConsumer) applyBalance(ctx context.Context, wallet *models.WalletMessage, key string) error {
+	invoice, err := c.getInvoiceByAddress(ctx, wallet.Address)
+	if err != nil {
+		return err
+	}
+
+	if wallet.Chain != invoice.Chain || wallet.Token != invoice.Token {
+		return nil
+	}
+
+	token, err := c.getToken(invoice)
 	if err != nil {
-		return fmt.Errorf("cryptoServiceClient.Transfer: %v", err)
+		return err
 	}
 
-	invoice.Status = desc.InvoiceStatus_SUCCESS
-	_, err = c.storage.UpdateInvoice(ctx, invoice)
+	invoicePayments, err := c.storage.ListInvoicePayments(ctx, []uuid.UUID{invoice.ID})
 	if err != nil {
-		return fmt.Errorf("storage.UpdateInvoice: %v", err)
+		return fmt.Errorf("storage.ListInvoicePayments: %w", err)
 	}
 
-	return nil
+	// recorded payments are the source of truth, balance is used
+	// for chains which transactions are not published
+	received := wallet.Balance
+	if len(invoicePayments) > 0 {
+		received = receivedUnits(invoicePayments, token)
+	}
+
+	return c.apply(ctx, invoice, token, received, key)
 }
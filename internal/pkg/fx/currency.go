@@ -0,0 +1,45 @@
+package fx
+
+import (
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/common"
+)
+
+const USD = "USD"
+
+// minorUnits is ISO-4217 exponent of supported fiat currencies
+var minorUnits = map[string]int{
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+}
+
+func IsSupported(currency string) bool {
+	_, ok := minorUnits[strings.ToUpper(currency)]
+	return ok
+}
+
+// ToMajor converts amount in minor units of currency, e.g. cents, to major units
+func ToMajor(amount int64, currency string) float64 {
+	return float64(amount) / math.Pow10(minorUnits[strings.ToUpper(currency)])
+}
+
+// ToMinor converts amount in major units of currency to minor units
+func ToMinor(amount float64, currency string) int64 {
+	return int64(math.Round(amount * math.Pow10(minorUnits[strings.ToUpper(currency)])))
+}
+
+// ToMajorRat converts amount in minor units of currency to exact amount in major units
+func ToMajorRat(amount int64, currency string) *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(amount), common.Pow10(minorUnits[strings.ToUpper(currency)]))
+}
+
+// RatToMinor converts exact amount in major units of currency to minor units
+// rounding to the nearest minor unit
+func RatToMinor(amount *big.Rat, currency string) int64 {
+	minor := new(big.Rat).Mul(amount, new(big.Rat).SetInt(common.Pow10(minorUnits[strings.ToUpper(currency)])))
+	return common.RoundRat(minor).Int64()
+}
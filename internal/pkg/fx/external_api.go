@@ -0,0 +1,79 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	external_api "github.com/fidesy-pay/invoices-service/pkg/external-api"
+	"google.golang.org/grpc"
+)
+
+// rateTTL is how long rate of external-api is reused, rates older than that are never used
+const rateTTL = time.Minute
+
+type (
+	ExternalAPI interface {
+		GetPrice(ctx context.Context, in *external_api.GetPriceRequest, opts ...grpc.CallOption) (*external_api.GetPriceResponse, error)
+	}
+
+	// ExternalAPIRateSource serves market rates of external-api, which prices currency
+	// by its ISO-4217 code the same way it prices tokens. Currencies external-api
+	// has no price of are rejected
+	ExternalAPIRateSource struct {
+		externalAPI ExternalAPI
+
+		mu    sync.Mutex
+		rates map[string]cachedRate
+	}
+
+	cachedRate struct {
+		rate      float64
+		fetchedAt time.Time
+	}
+)
+
+func NewExternalAPIRateSource(externalAPI ExternalAPI) *ExternalAPIRateSource {
+	return &ExternalAPIRateSource{
+		externalAPI: externalAPI,
+		rates:       make(map[string]cachedRate),
+	}
+}
+
+func (s *ExternalAPIRateSource) GetUSDRate(ctx context.Context, currency string) (float64, error) {
+	currency = strings.ToUpper(currency)
+	if currency == USD {
+		return 1, nil
+	}
+
+	s.mu.Lock()
+	cached, ok := s.rates[currency]
+	s.mu.Unlock()
+
+	if ok && time.Since(cached.fetchedAt) < rateTTL {
+		return cached.rate, nil
+	}
+
+	priceResp, err := s.externalAPI.GetPrice(ctx, &external_api.GetPriceRequest{
+		Symbol: currency,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w: currency = %q: externalAPI.GetPrice: %v", ErrRateUnavailable, currency, err)
+	}
+
+	rate := priceResp.GetPriceUsd()
+	if rate <= 0 {
+		return 0, fmt.Errorf("%w: currency = %q: invalid rate %v", ErrRateUnavailable, currency, rate)
+	}
+
+	s.mu.Lock()
+	s.rates[currency] = cachedRate{
+		rate:      rate,
+		fetchedAt: time.Now(),
+	}
+	s.mu.Unlock()
+
+	return rate, nil
+}
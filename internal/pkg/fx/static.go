@@ -0,0 +1,40 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrRateUnavailable is returned when rate of currency is not known, amounts in currency
+// are rejected instead of being converted with a wrong rate
+var ErrRateUnavailable = errors.New("usd rate is unavailable")
+
+// StaticRateSource serves fixed rates from config. Fixed rates go stale,
+// so it is used only in local environment
+type StaticRateSource struct {
+	usdRates map[string]float64
+}
+
+// NewStaticRateSource accepts price of one unit of currency in USD
+func NewStaticRateSource(usdRates map[string]float64) *StaticRateSource {
+	rates := make(map[string]float64, len(usdRates)+1)
+	for currency, rate := range usdRates {
+		rates[strings.ToUpper(currency)] = rate
+	}
+	rates[USD] = 1
+
+	return &StaticRateSource{
+		usdRates: rates,
+	}
+}
+
+func (s *StaticRateSource) GetUSDRate(_ context.Context, currency string) (float64, error) {
+	rate, ok := s.usdRates[strings.ToUpper(currency)]
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("%w: currency = %q", ErrRateUnavailable, currency)
+	}
+
+	return rate, nil
+}
@@ -0,0 +1,187 @@
+package invoicesservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/common"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/storage"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/fidesy/sdk/common/logger"
+	"github.com/fidesy/sdk/common/postgres"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// RetryPayout moves invoice back to SENDING_TO_CLIENT, transfer worker sends payout
+// with gas overrides saved on invoice
+func (s *Service) RetryPayout(ctx context.Context, input *RetryPayoutInput) (invoice *models.Invoice, err error) {
+	defer func() {
+		s.audit(ctx, input.Operator, "RetryPayout", &input.InvoiceID, input.Note, input, err)
+	}()
+
+	ctx = models.WithActor(ctx, models.ActorAdmin)
+
+	invoice, err = s.getInvoice(ctx, input.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.Status != desc.InvoiceStatus_MANUAL_CONTROL {
+		return nil, fmt.Errorf("%w: status = %s", ErrInvoiceNotInManualControl, invoice.Status.String())
+	}
+
+	invoice.GasLimit = nil
+	if input.GasLimit != nil {
+		invoice.GasLimit = lo.ToPtr(int(*input.GasLimit))
+	}
+	invoice.GasPrice = input.GasPrice
+	invoice.Status = desc.InvoiceStatus_SENDING_TO_CLIENT
+
+	// overrides which are not set are cleared, so that overrides of previous retry are not reused
+	invoice, err = s.storage.UpdateInvoice(ctx, invoice, storage.InvoiceUpdate{
+		Reason:            fmt.Sprintf("payout retried by %s: %s", input.Operator, input.Note),
+		ClearGasOverrides: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage.UpdateInvoice: %w", err)
+	}
+
+	return invoice, nil
+}
+
+// MarkResolved completes invoice which payout is resolved by operator outside of the service
+func (s *Service) MarkResolved(ctx context.Context, input *MarkResolvedInput) (invoice *models.Invoice, err error) {
+	defer func() {
+		s.audit(ctx, input.Operator, "MarkResolved", &input.InvoiceID, input.Note, input, err)
+	}()
+
+	ctx = models.WithActor(ctx, models.ActorAdmin)
+
+	invoice, err = s.getInvoice(ctx, input.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.Status != desc.InvoiceStatus_MANUAL_CONTROL {
+		return nil, fmt.Errorf("%w: status = %s", ErrInvoiceNotInManualControl, invoice.Status.String())
+	}
+
+	update := storage.InvoiceUpdate{
+		Reason: fmt.Sprintf("resolved by %s: %s", input.Operator, input.Note),
+	}
+
+	if input.TransactionHash != nil {
+		payoutTransactions, err := s.storage.ListPayoutTransactions(ctx, []uuid.UUID{invoice.ID})
+		if err != nil {
+			return nil, fmt.Errorf("storage.ListPayoutTransactions: %w", err)
+		}
+
+		// payout is recorded in the same transaction as status
+		update.PayoutTransaction = &models.PayoutTransaction{
+			InvoiceID:       invoice.ID,
+			TransactionHash: *input.TransactionHash,
+			Attempt:         len(payoutTransactions) + 1,
+		}
+	}
+
+	invoice.Status = input.Status
+
+	invoice, err = s.storage.UpdateInvoice(ctx, invoice, update)
+	if err != nil {
+		return nil, fmt.Errorf("storage.UpdateInvoice: %w", err)
+	}
+
+	return invoice, nil
+}
+
+// ListStuckInvoices returns invoices which payout is not completed, oldest first
+func (s *Service) ListStuckInvoices(
+	ctx context.Context,
+	operator string,
+	req *desc.ListStuckInvoicesRequest,
+) (invoices []*models.Invoice, err error) {
+	defer func() {
+		s.audit(ctx, operator, "ListStuckInvoices", nil, "", req, err)
+	}()
+
+	statuses := req.GetStatusIn()
+	if len(statuses) == 0 {
+		statuses = []desc.InvoiceStatus{desc.InvoiceStatus_MANUAL_CONTROL, desc.InvoiceStatus_SENDING_TO_CLIENT}
+	}
+
+	invoices, err = s.storage.ListInvoices(
+		ctx,
+		storage.ListInvoicesFilter{
+			StatusIn: statuses,
+			Sort: storage.InvoiceSort{
+				Field: storage.InvoiceSortFieldCreatedAt,
+				Asc:   true,
+			},
+		},
+		postgres.NewPagination(req.GetPage(), req.GetPerPage()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage.ListInvoices: %w", err)
+	}
+
+	return invoices, nil
+}
+
+func (s *Service) ListAuditLog(ctx context.Context, req *desc.ListAuditLogRequest) ([]*models.AuditLogEntry, error) {
+	var err error
+
+	reqFilter := req.GetFilter()
+
+	filter := storage.ListAuditLogFilter{
+		OperatorIn: reqFilter.GetOperatorIn(),
+	}
+	if len(reqFilter.GetInvoiceIdIn()) > 0 {
+		filter.InvoiceIDIn, err = common.ConvertToUUIDs(reqFilter.GetInvoiceIdIn())
+		if err != nil {
+			return nil, fmt.Errorf("common.ConvertToUUIDs: %w", err)
+		}
+	}
+
+	entries, err := s.storage.ListAuditLog(ctx, filter, postgres.NewPagination(req.GetPage(), req.GetPerPage()))
+	if err != nil {
+		return nil, fmt.Errorf("storage.ListAuditLog: %w", err)
+	}
+
+	return entries, nil
+}
+
+// audit records call of admin API with its result, failure to record
+// does not fail the call as the action is already done
+func (s *Service) audit(
+	ctx context.Context,
+	operator, action string,
+	invoiceID *uuid.UUID,
+	note string,
+	request interface{},
+	actionErr error,
+) {
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		logger.Errorf("audit: json.Marshal: %v", err)
+		return
+	}
+
+	entry := &models.AuditLogEntry{
+		Operator:  operator,
+		Action:    action,
+		InvoiceID: invoiceID,
+		Note:      note,
+		Request:   string(requestJSON),
+	}
+	if actionErr != nil {
+		entry.Error = lo.ToPtr(actionErr.Error())
+	}
+
+	_, err = s.storage.CreateAuditLogEntry(ctx, entry)
+	if err != nil {
+		logger.Errorf("audit: storage.CreateAuditLogEntry: %v", err)
+	}
+}
@@ -11,9 +11,35 @@ var (
 		return fmt.Errorf("invoice not found by id = %q", invoiceID.String())
 	}
 
+	ErrWebhookDeliveryNotFoundByID = func(deliveryID uuid.UUID) error {
+		return fmt.Errorf("webhook delivery not found by id = %q", deliveryID.String())
+	}
+
 	ErrInvoiceNotFoundByAddress = func(address string) error {
 		return fmt.Errorf("invoice not found by address = %q", address)
 	}
 
-	ErrInvoiceAlreadyCompleted = errors.New("invoice is already completed")
+	ErrInvoiceNotCancellable = errors.New("invoice can not be cancelled")
+
+	ErrIdempotencyKeyReused = errors.New("idempotency key is already used for another invoice")
+
+	ErrInvoiceNotRefundable = errors.New("invoice can not be refunded")
+
+	ErrRefundTokenNotSupported = errors.New("refunds are supported only in native token of chain")
+
+	ErrInvoiceNotQuotable = errors.New("invoice is not awaiting payment")
+
+	ErrTokenNotSupported = errors.New("token is not supported")
+
+	ErrTokenAmountBelowMin = errors.New("token amount is below min amount of token")
+
+	ErrTokenAmountAboveMax = errors.New("token amount is above max amount of token")
+
+	ErrPayoutFeeExceeded = errors.New("payout fee exceeds max fee of invoice")
+
+	ErrInvoiceNotInManualControl = errors.New("invoice is not in manual control")
+
+	ErrInvalidPageToken = errors.New("invalid page token")
+
+	ErrWebhookAddressNotAllowed = errors.New("webhook address is not allowed")
 )
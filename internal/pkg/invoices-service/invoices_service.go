@@ -2,15 +2,19 @@ package invoicesservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math/big"
+	"net/http"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/fidesy-pay/invoices-service/internal/config"
 	"github.com/fidesy-pay/invoices-service/internal/pkg/common"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/fx"
 	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
 	"github.com/fidesy-pay/invoices-service/internal/pkg/storage"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/tokens"
 	crypto_service "github.com/fidesy-pay/invoices-service/pkg/crypto-service"
 	external_api "github.com/fidesy-pay/invoices-service/pkg/external-api"
 	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
@@ -21,26 +25,100 @@ import (
 	"google.golang.org/grpc"
 )
 
+const (
+	// transferLease covers all payout attempts of single invoice
+	transferLease = 5 * time.Minute
+	// payoutTimeout bounds payout attempts, it is shorter than transferLease so that
+	// invoice is never claimed by other instance while its transfer is being sent
+	payoutTimeout = 4 * time.Minute
+
+	// watchPollInterval is how often watched invoice is read from storage
+	watchPollInterval = 5 * time.Second
+
+	defaultExpireWorkerInterval  = 5 * time.Second
+	defaultExpireWorkerBatchSize = 100
+
+	// processedMessagesRetention is how long consumed messages are deduplicated,
+	// messages are consumed from the newest offset so older ones are never redelivered
+	processedMessagesRetention      = 7 * 24 * time.Hour
+	processedMessagesCleanInterval  = time.Hour
+	processedMessagesCleanBatchSize = 1000
+)
+
 type (
 	Service struct {
 		storage             Storage
 		cryptoServiceClient CryptoServiceClient
 		externalAPI         ExternalAPI
+		fxRateSource        FXRateSource
+		tokenRegistry       TokenRegistry
+		watcherHub          WatcherHub
+		httpClient          HTTPClient
+		// workerID identifies instance in leases of rows claimed by its workers
+		workerID string
 	}
 
 	CryptoServiceClient interface {
 		AcceptCrypto(ctx context.Context, in *crypto_service.AcceptCryptoRequest, opts ...grpc.CallOption) (*crypto_service.AcceptCryptoResponse, error)
 		Transfer(ctx context.Context, in *crypto_service.TransferRequest, opts ...grpc.CallOption) (*crypto_service.TransferResponse, error)
+		SuggestGasPrice(ctx context.Context, in *crypto_service.SuggestGasPriceRequest, opts ...grpc.CallOption) (*crypto_service.SuggestGasPriceResponse, error)
+		SuggestFee(ctx context.Context, in *crypto_service.SuggestFeeRequest, opts ...grpc.CallOption) (*crypto_service.SuggestFeeResponse, error)
 	}
 
 	ExternalAPI interface {
 		GetPrice(ctx context.Context, in *external_api.GetPriceRequest, opts ...grpc.CallOption) (*external_api.GetPriceResponse, error)
 	}
 
+	// FXRateSource converts fiat invoice amounts to USD
+	FXRateSource interface {
+		GetUSDRate(ctx context.Context, currency string) (float64, error)
+	}
+
+	// TokenRegistry describes supported tokens, every amount of token is converted with it
+	TokenRegistry interface {
+		Get(chain, symbol string) (tokens.Token, bool)
+		Native(chain string) (tokens.Token, bool)
+		ListEnabled() []tokens.Token
+	}
+
+	HTTPClient interface {
+		Do(req *http.Request) (*http.Response, error)
+	}
+
+	WatcherHub interface {
+		Subscribe(invoiceID uuid.UUID) (<-chan *models.Invoice, func())
+	}
+
 	Storage interface {
 		CreateInvoice(ctx context.Context, invoice *models.Invoice) (*models.Invoice, error)
 		ListInvoices(ctx context.Context, filter storage.ListInvoicesFilter, pagination postgres.Pagination) ([]*models.Invoice, error)
-		UpdateInvoice(ctx context.Context, invoice *models.Invoice) (*models.Invoice, error)
+		CountInvoices(ctx context.Context, filter storage.ListInvoicesFilter) (uint64, error)
+		UpdateInvoice(ctx context.Context, invoice *models.Invoice, update storage.InvoiceUpdate) (*models.Invoice, error)
+		ClaimInvoices(ctx context.Context, filter storage.ListInvoicesFilter, owner string, lease time.Duration, limit uint64) ([]*models.Invoice, error)
+		ReleaseInvoice(ctx context.Context, invoiceID uuid.UUID, owner string) error
+		ExpireInvoices(ctx context.Context, now time.Time, limit uint64, reason string) ([]*models.Invoice, error)
+		DeleteProcessedMessages(ctx context.Context, before time.Time, limit uint64) (int64, error)
+		ListInvoiceStatusHistory(ctx context.Context, invoiceID uuid.UUID) ([]*models.InvoiceStatusChange, error)
+
+		ListPayoutTransactions(ctx context.Context, invoiceIDs []uuid.UUID) ([]*models.PayoutTransaction, error)
+		CreatePayoutTransaction(ctx context.Context, transaction *models.PayoutTransaction) (*models.PayoutTransaction, error)
+
+		ListInvoicePayments(ctx context.Context, invoiceIDs []uuid.UUID) ([]*models.InvoicePayment, error)
+
+		ListAuditLog(ctx context.Context, filter storage.ListAuditLogFilter, pagination postgres.Pagination) ([]*models.AuditLogEntry, error)
+		CreateAuditLogEntry(ctx context.Context, entry *models.AuditLogEntry) (*models.AuditLogEntry, error)
+
+		CreateRefund(ctx context.Context, refund *models.Refund, limit storage.RefundLimit) (*models.Refund, error)
+		UpdateRefund(ctx context.Context, refund *models.Refund) (*models.Refund, error)
+		ClaimRefunds(ctx context.Context, statuses []desc.RefundStatus, owner string, lease time.Duration, limit uint64) ([]*models.Refund, error)
+		ReleaseRefund(ctx context.Context, refundID uuid.UUID, owner string) error
+
+		SaveWebhook(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error)
+		ListWebhooks(ctx context.Context, clientIDs []uuid.UUID) ([]*models.Webhook, error)
+		ListWebhookDeliveries(ctx context.Context, filter storage.ListWebhookDeliveriesFilter, pagination postgres.Pagination) ([]*models.WebhookDelivery, error)
+		UpdateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error)
+		ClaimWebhookDeliveries(ctx context.Context, filter storage.ListWebhookDeliveriesFilter, owner string, lease time.Duration, limit uint64) ([]*models.WebhookDelivery, error)
+		ReleaseWebhookDelivery(ctx context.Context, deliveryID uuid.UUID, owner string) error
 	}
 )
 
@@ -49,56 +127,124 @@ func New(
 	storage Storage,
 	cryptoServiceClient CryptoServiceClient,
 	externalAPI ExternalAPI,
+	fxRateSource FXRateSource,
+	tokenRegistry TokenRegistry,
+	watcherHub WatcherHub,
+	httpClient HTTPClient,
 ) *Service {
 	service := &Service{
 		storage:             storage,
 		cryptoServiceClient: cryptoServiceClient,
 		externalAPI:         externalAPI,
+		fxRateSource:        fxRateSource,
+		tokenRegistry:       tokenRegistry,
+		watcherHub:          watcherHub,
+		httpClient:          httpClient,
+		workerID:            uuid.NewString(),
 	}
 
 	go service.cleanExpiredInvoicesWorker(ctx)
 	go service.transferWorker(ctx)
+	go service.refundWorker(ctx)
+	go service.webhookWorker(ctx)
+	go service.cleanProcessedMessagesWorker(ctx)
 
 	return service
 }
 
 func (s *Service) CreateInvoice(ctx context.Context, input *CreateInvoiceInput) (*models.Invoice, error) {
+	ttl := config.Get(config.ExpireInterval).(time.Duration)
+	if input.TTL != nil {
+		ttl = *input.TTL
+	}
+
+	usdAmount, err := s.amountInUSD(ctx, input.Amount, input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
 	invoice := &models.Invoice{
-		ID:             uuid.New(),
-		ClientID:       input.ClientID,
-		UsdCentsAmount: input.UsdCentsAmount,
-		Status:         desc.InvoiceStatus_NEW,
-		CreatedAt:      time.Now(),
+		ID:                uuid.New(),
+		ClientID:          input.ClientID,
+		Currency:          input.Currency,
+		Amount:            input.Amount,
+		UsdCentsAmount:    fx.RatToMinor(usdAmount, fx.USD),
+		Status:            desc.InvoiceStatus_NEW,
+		CreatedAt:         now,
+		IdempotencyKey:    input.IdempotencyKey,
+		ExternalReference: input.ExternalReference,
+		Description:       input.Description,
+		Metadata:          input.Metadata,
+		ExpiresAt:         now.Add(ttl),
 	}
 
-	var err error
 	invoice, err = s.storage.CreateInvoice(ctx, invoice)
 	if err != nil {
+		if errors.Is(err, postgres.ErrAlreadyExists) && input.IdempotencyKey != nil {
+			return s.getInvoiceByIdempotencyKey(ctx, input)
+		}
+
 		return nil, fmt.Errorf("storage.CreateInvoice: %w", err)
 	}
 
 	return invoice, nil
 }
 
-func (s *Service) UpdateInvoice(ctx context.Context, input *UpdateInvoiceInput) (*models.Invoice, error) {
+// getInvoiceByIdempotencyKey returns invoice created by previous attempt of the same request
+func (s *Service) getInvoiceByIdempotencyKey(ctx context.Context, input *CreateInvoiceInput) (*models.Invoice, error) {
 	invoices, err := s.storage.ListInvoices(
 		ctx,
 		storage.ListInvoicesFilter{
-			IDIn: []uuid.UUID{input.InvoiceID},
+			ClientIDIn:       []uuid.UUID{input.ClientID},
+			IdempotencyKeyIn: []string{*input.IdempotencyKey},
 		},
 		postgres.NewPagination(1, 1),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("storage.ListInvoices: %w", err)
 	}
+
 	if len(invoices) == 0 {
-		return nil, ErrInvoiceNotFoundByID(input.InvoiceID)
+		return nil, fmt.Errorf("invoice not found by idempotency key = %q", *input.IdempotencyKey)
 	}
 
 	invoice := invoices[0]
+	if invoice.Amount != input.Amount || invoice.Currency != input.Currency {
+		return nil, ErrIdempotencyKeyReused
+	}
+
+	return invoice, nil
+}
+
+func (s *Service) UpdateInvoice(ctx context.Context, input *UpdateInvoiceInput) (*models.Invoice, error) {
+	// checked before any external call, so that unsupported pair never reaches crypto service
+	token, ok := s.tokenRegistry.Get(input.Chain, input.Token)
+	if !ok || !token.Enabled {
+		return nil, fmt.Errorf("%w: chain = %q, token = %q", ErrTokenNotSupported, input.Chain, input.Token)
+	}
+
+	invoice, err := s.getInvoice(ctx, input.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkVersion(invoice, input.Version); err != nil {
+		return nil, err
+	}
+
+	// checked before address is requested, storage checks it again on update
+	if !models.CanTransition(invoice.Status, desc.InvoiceStatus_PENDING) {
+		return nil, &models.StatusTransitionError{From: invoice.Status, To: desc.InvoiceStatus_PENDING}
+	}
 
-	if invoice.Status == desc.InvoiceStatus_SUCCESS {
-		return nil, ErrInvoiceAlreadyCompleted
+	// priced before address is requested, so that amount out of token bounds
+	// never allocates address
+	invoice.Chain = input.Chain
+	invoice.Token = input.Token
+	err = s.quote(ctx, invoice)
+	if err != nil {
+		return nil, err
 	}
 
 	acceptCryptoResp, err := s.cryptoServiceClient.AcceptCrypto(ctx, &crypto_service.AcceptCryptoRequest{
@@ -110,33 +256,279 @@ func (s *Service) UpdateInvoice(ctx context.Context, input *UpdateInvoiceInput)
 		return nil, fmt.Errorf("cryptoServiceClient.AcceptCrypto: %w", err)
 	}
 
-	tokenPriceResp, err := s.externalAPI.GetPrice(ctx, &external_api.GetPriceRequest{
-		Symbol: input.Token,
+	invoice.Status = desc.InvoiceStatus_PENDING
+	invoice.Address = strings.ToLower(acceptCryptoResp.GetAddress())
+	invoice.PayerClientID = input.PayerClientID
+
+	invoice, err = s.storage.UpdateInvoice(ctx, invoice, storage.InvoiceUpdate{
+		Reason: fmt.Sprintf("payment in %s on %s requested", input.Token, input.Chain),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("coinGeckoAPIClient.GetPrice: %w", err)
+		return nil, fmt.Errorf("storage.UpdateInvoice: %w", err)
 	}
 
-	tokenAmount := float64(invoice.UsdCentsAmount) / (100 * tokenPriceResp.GetPriceUsd())
-	invoice.TokenAmount = &tokenAmount
-	invoice.Chain = input.Chain
-	invoice.Token = input.Token
-	invoice.Status = desc.InvoiceStatus_PENDING
-	invoice.Address = strings.ToLower(acceptCryptoResp.GetAddress())
-	invoice.PayerClientID = input.PayerClientID
+	return invoice, nil
+}
 
-	invoice, err = s.storage.UpdateInvoice(ctx, invoice)
+// RefreshQuote re-prices token amount of awaiting payment invoice with current token price
+func (s *Service) RefreshQuote(ctx context.Context, invoiceID uuid.UUID, version *int64) (*models.Invoice, error) {
+	invoice, err := s.getInvoice(ctx, invoiceID)
 	if err != nil {
-		return nil, fmt.Errorf("storage.CreateInvoice: %w", err)
+		return nil, err
+	}
+
+	if err = checkVersion(invoice, version); err != nil {
+		return nil, err
+	}
+
+	if invoice.Status != desc.InvoiceStatus_PENDING && invoice.Status != desc.InvoiceStatus_PARTIALLY_PAID {
+		return nil, fmt.Errorf("%w: status = %s", ErrInvoiceNotQuotable, invoice.Status.String())
+	}
+
+	err = s.quote(ctx, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err = s.storage.UpdateInvoice(ctx, invoice, storage.InvoiceUpdate{})
+	if err != nil {
+		return nil, fmt.Errorf("storage.UpdateInvoice: %w", err)
 	}
 
 	return invoice, nil
 }
 
+// quote locks token amount of invoice with current token price for quote-ttl
+func (s *Service) quote(ctx context.Context, invoice *models.Invoice) error {
+	token, ok := s.tokenRegistry.Get(invoice.Chain, invoice.Token)
+	if !ok {
+		return fmt.Errorf("%w: chain = %q, token = %q", ErrTokenNotSupported, invoice.Chain, invoice.Token)
+	}
+
+	tokenPriceResp, err := s.externalAPI.GetPrice(ctx, &external_api.GetPriceRequest{
+		Symbol: invoice.Token,
+	})
+	if err != nil {
+		return fmt.Errorf("coinGeckoAPIClient.GetPrice: %w", err)
+	}
+
+	usdAmount, err := s.amountInUSD(ctx, invoice.Amount, invoice.Currency)
+	if err != nil {
+		return err
+	}
+
+	if tokenPriceResp.GetPriceUsd() <= 0 {
+		return fmt.Errorf("invalid price of %s: %v", invoice.Token, tokenPriceResp.GetPriceUsd())
+	}
+
+	tokenUnits := token.Quote(usdAmount, tokenPriceResp.GetPriceUsd())
+	if tokenUnits.Cmp(token.MinBaseUnits()) < 0 {
+		return fmt.Errorf(
+			"%w: amount = %v, min = %v %s",
+			ErrTokenAmountBelowMin, token.FromBaseUnits(tokenUnits), token.MinAmount, token.Symbol,
+		)
+	}
+
+	if maxUnits := token.MaxBaseUnits(); maxUnits != nil && tokenUnits.Cmp(maxUnits) > 0 {
+		return fmt.Errorf(
+			"%w: amount = %v, max = %v %s",
+			ErrTokenAmountAboveMax, token.FromBaseUnits(tokenUnits), token.MaxAmount, token.Symbol,
+		)
+	}
+
+	invoice.TokenAmountUnits = models.NewUnits(tokenUnits)
+	invoice.TokenDecimals = lo.ToPtr(int32(token.Decimals))
+	invoice.TokenAmount = lo.ToPtr(token.FromBaseUnits(tokenUnits))
+	invoice.PriceUsd = lo.ToPtr(tokenPriceResp.GetPriceUsd())
+	invoice.QuoteExpiresAt = lo.ToPtr(time.Now().Add(config.Get(config.QuoteTTL).(time.Duration)))
+
+	return nil
+}
+
 func (s *Service) CheckInvoice(ctx context.Context, invoiceIDStr string) (*models.Invoice, error) {
 	// we validate ID in handler logic
 	invoiceID := uuid.MustParse(invoiceIDStr)
 
+	invoice, err := s.getInvoice(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.PayoutTransactions, err = s.storage.ListPayoutTransactions(ctx, []uuid.UUID{invoiceID})
+	if err != nil {
+		return nil, fmt.Errorf("storage.ListPayoutTransactions: %w", err)
+	}
+
+	return invoice, nil
+}
+
+// WatchInvoice sends invoice every time its status or received amount changes
+// until invoice reaches final status or ctx is done
+func (s *Service) WatchInvoice(ctx context.Context, invoiceID uuid.UUID, send func(invoice *models.Invoice) error) error {
+	// subscribe before reading current state to not miss updates in between
+	updates, unsubscribe := s.watcherHub.Subscribe(invoiceID)
+	defer unsubscribe()
+
+	invoice, err := s.getInvoice(ctx, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	if err = send(invoice); err != nil {
+		return fmt.Errorf("send: %w", err)
+	}
+
+	// updates are published only to watchers of instance which made them,
+	// invoice changed by other instances is read on poll
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for !invoice.IsFinal() {
+		var update *models.Invoice
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case update = <-updates:
+		case <-ticker.C:
+			update, err = s.getInvoice(ctx, invoiceID)
+			if err != nil {
+				logger.Errorf("WatchInvoice: getInvoice: %v", err)
+				continue
+			}
+		}
+
+		// update published before the one already read
+		if update.Version <= invoice.Version {
+			continue
+		}
+
+		changed := update.Status != invoice.Status ||
+			update.ReceivedAmountUnits.String() != invoice.ReceivedAmountUnits.String()
+
+		invoice = update
+		if !changed {
+			continue
+		}
+
+		if err = send(invoice); err != nil {
+			return fmt.Errorf("send: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) CancelInvoice(ctx context.Context, input *CancelInvoiceInput) (*models.Invoice, error) {
+	invoice, err := s.getInvoice(ctx, input.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkVersion(invoice, input.Version); err != nil {
+		return nil, err
+	}
+
+	// only unpaid invoices can be voided, once funds are forwarded to client it is too late
+	if invoice.Status == desc.InvoiceStatus_CANCELLED || !models.CanTransition(invoice.Status, desc.InvoiceStatus_CANCELLED) {
+		return nil, fmt.Errorf("%w: status = %s", ErrInvoiceNotCancellable, invoice.Status.String())
+	}
+
+	invoice.Status = desc.InvoiceStatus_CANCELLED
+	invoice.CancellationReason = lo.ToPtr(input.Reason)
+
+	invoice, err = s.storage.UpdateInvoice(ctx, invoice, storage.InvoiceUpdate{
+		Reason: input.Reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage.UpdateInvoice: %w", err)
+	}
+
+	return invoice, nil
+}
+
+// GetInvoiceHistory returns status transitions of invoice in order they happened
+func (s *Service) GetInvoiceHistory(ctx context.Context, invoiceID uuid.UUID) ([]*models.InvoiceStatusChange, error) {
+	// distinguishes unknown invoice from invoice without history
+	_, err := s.getInvoice(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.storage.ListInvoiceStatusHistory(ctx, invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("storage.ListInvoiceStatusHistory: %w", err)
+	}
+
+	return changes, nil
+}
+
+// checkVersion rejects update of invoice which is changed since caller read it,
+// storage compares version of invoice again on update
+func checkVersion(invoice *models.Invoice, expected *int64) error {
+	if expected == nil || invoice.Version == *expected {
+		return nil
+	}
+
+	return fmt.Errorf("%w: version = %d, expected = %d", models.ErrVersionConflict, invoice.Version, *expected)
+}
+
+// ListInvoicePayments returns transactions payers sent to invoice address
+func (s *Service) ListInvoicePayments(ctx context.Context, invoiceID uuid.UUID) ([]*models.InvoicePayment, error) {
+	_, err := s.getInvoice(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.storage.ListInvoicePayments(ctx, []uuid.UUID{invoiceID})
+	if err != nil {
+		return nil, fmt.Errorf("storage.ListInvoicePayments: %w", err)
+	}
+
+	return payments, nil
+}
+
+// ListPaymentMethods returns enabled tokens of chain, or of all chains if chain is nil
+func (s *Service) ListPaymentMethods(_ context.Context, chain *string) []*models.PaymentMethod {
+	methods := make([]*models.PaymentMethod, 0)
+	for _, token := range s.tokenRegistry.ListEnabled() {
+		if chain != nil && !strings.EqualFold(token.Chain, *chain) {
+			continue
+		}
+
+		method := &models.PaymentMethod{
+			Chain:     token.Chain,
+			Token:     token.Symbol,
+			Decimals:  int32(token.Decimals),
+			Contract:  token.Contract,
+			MinAmount: token.Format(token.MinBaseUnits()),
+		}
+
+		if maxUnits := token.MaxBaseUnits(); maxUnits != nil {
+			method.MaxAmount = token.Format(maxUnits)
+		}
+
+		methods = append(methods, method)
+	}
+
+	return methods
+}
+
+// amountInUSD converts amount in minor units of fiat currency to exact amount in USD
+func (s *Service) amountInUSD(ctx context.Context, amount int64, currency string) (*big.Rat, error) {
+	if currency == fx.USD {
+		return fx.ToMajorRat(amount, currency), nil
+	}
+
+	rate, err := s.fxRateSource.GetUSDRate(ctx, currency)
+	if err != nil {
+		return nil, fmt.Errorf("fxRateSource.GetUSDRate: %w", err)
+	}
+
+	return new(big.Rat).Mul(fx.ToMajorRat(amount, currency), common.FloatToRat(rate)), nil
+}
+
+func (s *Service) getInvoice(ctx context.Context, invoiceID uuid.UUID) (*models.Invoice, error) {
 	invoices, err := s.storage.ListInvoices(
 		ctx,
 		storage.ListInvoicesFilter{
@@ -155,7 +547,7 @@ func (s *Service) CheckInvoice(ctx context.Context, invoiceIDStr string) (*model
 	return invoices[0], nil
 }
 
-func (s *Service) ListInvoices(ctx context.Context, req *desc.ListInvoicesRequest) ([]*models.Invoice, error) {
+func (s *Service) ListInvoices(ctx context.Context, req *desc.ListInvoicesRequest) (*ListInvoicesOutput, error) {
 	var err error
 
 	reqFilter := req.GetFilter()
@@ -179,16 +571,100 @@ func (s *Service) ListInvoices(ctx context.Context, req *desc.ListInvoicesReques
 		filter.StatusIn = reqFilter.InvoiceStatusIn
 	}
 
-	invoices, err := s.storage.ListInvoices(ctx, filter, postgres.NewPagination(req.GetPage(), req.GetPerPage()))
+	if len(reqFilter.ExternalReferenceIn) > 0 {
+		filter.ExternalReferenceIn = reqFilter.ExternalReferenceIn
+	}
+
+	if reqFilter.GetCreatedAtFrom() != nil {
+		filter.CreatedAtGte = lo.ToPtr(reqFilter.GetCreatedAtFrom().AsTime())
+	}
+
+	if reqFilter.GetCreatedAtTo() != nil {
+		filter.CreatedAtLt = lo.ToPtr(reqFilter.GetCreatedAtTo().AsTime())
+	}
+
+	if len(reqFilter.GetChainIn()) > 0 {
+		filter.ChainIn = reqFilter.GetChainIn()
+	}
+
+	if len(reqFilter.GetTokenIn()) > 0 {
+		filter.TokenIn = reqFilter.GetTokenIn()
+	}
+
+	if len(reqFilter.GetAddressIn()) > 0 {
+		filter.AddressIn = lo.Map(reqFilter.GetAddressIn(), func(address string, _ int) string {
+			return strings.ToLower(address)
+		})
+	}
+
+	if len(reqFilter.GetPayerClientIdIn()) > 0 {
+		filter.PayerClientIDIn, err = common.ConvertToUUIDs(reqFilter.GetPayerClientIdIn())
+		if err != nil {
+			return nil, fmt.Errorf("common.ConvertToUUIDs: %w", err)
+		}
+	}
+
+	if reqFilter.UsdAmountFrom != nil {
+		filter.UsdCentsAmountGte = lo.ToPtr(fx.ToMinor(reqFilter.GetUsdAmountFrom(), fx.USD))
+	}
+
+	if reqFilter.UsdAmountTo != nil {
+		filter.UsdCentsAmountLte = lo.ToPtr(fx.ToMinor(reqFilter.GetUsdAmountTo(), fx.USD))
+	}
+
+	if req.GetSortField() == desc.ListInvoicesRequest_USD_AMOUNT {
+		filter.Sort.Field = storage.InvoiceSortFieldUsdCentsAmount
+	} else {
+		filter.Sort.Field = storage.InvoiceSortFieldCreatedAt
+	}
+	filter.Sort.Asc = req.GetSortDirection() == desc.ListInvoicesRequest_ASC
+
+	output := &ListInvoicesOutput{}
+	if req.GetWithTotalCount() {
+		totalCount, err := s.storage.CountInvoices(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("storage.CountInvoices: %w", err)
+		}
+
+		output.TotalCount = lo.ToPtr(totalCount)
+	}
+
+	pagination := postgres.NewPagination(req.GetPage(), req.GetPerPage())
+	if req.GetPageToken() != "" {
+		filter.After, err = decodePageToken(filter.Sort, req.GetPageToken())
+		if err != nil {
+			return nil, err
+		}
+
+		// offset is replaced by cursor
+		pagination = postgres.NewPagination(1, req.GetPerPage())
+	}
+
+	output.Invoices, err = s.storage.ListInvoices(ctx, filter, pagination)
 	if err != nil {
 		return nil, fmt.Errorf("storage.ListInvoices: %w", err)
 	}
 
-	return invoices, nil
+	if len(output.Invoices) > 0 && uint64(len(output.Invoices)) == pagination.Limit() {
+		output.NextPageToken, err = encodePageToken(filter.Sort, output.Invoices[len(output.Invoices)-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return output, nil
 }
 
 func (s *Service) cleanExpiredInvoicesWorker(ctx context.Context) {
-	ticker := time.NewTicker(5 * time.Second)
+	ctx = context.WithValue(ctx, "skip_span", true)
+	ctx = models.WithActor(ctx, models.ActorExpiryWorker)
+
+	interval := config.Get(config.ExpireWorkerInterval).(time.Duration)
+	if interval <= 0 {
+		interval = defaultExpireWorkerInterval
+	}
+
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
@@ -196,119 +672,194 @@ func (s *Service) cleanExpiredInvoicesWorker(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			go s.cleanExpiredInvoices(ctx)
+			// runs in worker goroutine, so runs never overlap
+			s.cleanExpiredInvoices(ctx)
 		}
 	}
 }
 
-func (s *Service) cleanExpiredInvoices(ctx context.Context) {
+func (s *Service) cleanProcessedMessagesWorker(ctx context.Context) {
 	ctx = context.WithValue(ctx, "skip_span", true)
 
-	invoices, err := s.storage.ListInvoices(
-		ctx,
-		storage.ListInvoicesFilter{
-			StatusIn:    []desc.InvoiceStatus{desc.InvoiceStatus_NEW, desc.InvoiceStatus_PENDING},
-			CreatedAtLt: lo.ToPtr(time.Now().Add(-config.Get(config.ExpireInterval).(time.Duration))),
-		},
-		postgres.NewPagination(1, 100),
-	)
-	if err != nil {
-		logger.Errorf("cleanExpiredInvoices: storage.ListInvoices: %w", err)
-		return
+	ticker := time.NewTicker(processedMessagesCleanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.cleanProcessedMessages(ctx)
+		}
 	}
+}
 
-	for _, invoice := range invoices {
-		invoice.Status = desc.InvoiceStatus_EXPIRED
-		_, err = s.storage.UpdateInvoice(ctx, invoice)
+// cleanProcessedMessages deletes processed messages older than retention in batches
+func (s *Service) cleanProcessedMessages(ctx context.Context) {
+	before := time.Now().Add(-processedMessagesRetention)
+
+	for ctx.Err() == nil {
+		deleted, err := s.storage.DeleteProcessedMessages(ctx, before, processedMessagesCleanBatchSize)
 		if err != nil {
-			logger.Errorf("cleanExpiredInvoices: storage.UpdateInvoice: %w", err)
+			logger.Errorf("cleanProcessedMessages: storage.DeleteProcessedMessages: %v", err)
+			return
+		}
+
+		if deleted < processedMessagesCleanBatchSize {
+			return
+		}
+	}
+}
+
+// cleanExpiredInvoices expires invoices in batches until backlog is drained
+func (s *Service) cleanExpiredInvoices(ctx context.Context) {
+	batchSize := config.Get(config.ExpireWorkerBatchSize).(uint64)
+	if batchSize == 0 {
+		batchSize = defaultExpireWorkerBatchSize
+	}
+
+	for ctx.Err() == nil {
+		invoices, err := s.storage.ExpireInvoices(ctx, time.Now(), batchSize, "not paid until expires_at")
+		if err != nil {
+			logger.Errorf("cleanExpiredInvoices: storage.ExpireInvoices: %v", err)
+			return
+		}
+
+		if uint64(len(invoices)) < batchSize {
+			return
 		}
 	}
 }
 
 func (s *Service) transferWorker(ctx context.Context) {
 	ctx = context.WithValue(ctx, "skip_span", true)
+	ctx = models.WithActor(ctx, models.ActorTransferWorker)
 
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
-	transferFunc := s.transferCallback()
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			go transferFunc(ctx)
+			go s.transferInvoices(ctx)
 		}
 	}
 }
 
-func (s *Service) transferCallback() func(ctx context.Context) {
-	var (
-		locks = make(map[string]struct{})
-		mu    sync.RWMutex
+// transferInvoices claims invoices waiting for payout, invoice claimed by one
+// instance is not claimed by others until it is released or its lease expires
+func (s *Service) transferInvoices(ctx context.Context) {
+	invoices, err := s.storage.ClaimInvoices(
+		ctx,
+		storage.ListInvoicesFilter{
+			StatusIn: []desc.InvoiceStatus{desc.InvoiceStatus_SENDING_TO_CLIENT},
+		},
+		s.workerID,
+		transferLease,
+		100,
 	)
+	if err != nil {
+		logger.Errorf("transferWorker: storage.ClaimInvoices: %v", err)
+		return
+	}
 
-	return func(ctx context.Context) {
-		invoices, err := s.storage.ListInvoices(
-			ctx,
-			storage.ListInvoicesFilter{
-				StatusIn: []desc.InvoiceStatus{desc.InvoiceStatus_SENDING_TO_CLIENT},
-			},
-			postgres.NewPagination(1, 100),
-		)
-		if err != nil {
-			logger.Errorf("transferWorker: storage.ListInvoices: %w", err)
-			return
-		}
+	for _, invoice := range invoices {
+		invoice := invoice
 
-		for _, invoice := range invoices {
-			invoice := invoice
+		go func() {
+			defer s.releaseInvoice(ctx, invoice.ID)
 
-			mu.RLock()
-			_, ok := locks[invoice.ID.String()]
-			mu.RUnlock()
-			if ok {
-				continue
-			}
+			s.completeInvoice(ctx, invoice)
+		}()
+	}
+}
 
-			mu.Lock()
-			locks[invoice.ID.String()] = struct{}{}
-			mu.Unlock()
+func (s *Service) releaseInvoice(ctx context.Context, invoiceID uuid.UUID) {
+	err := s.storage.ReleaseInvoice(ctx, invoiceID, s.workerID)
+	if err != nil {
+		logger.Errorf("storage.ReleaseInvoice: %v", err)
+	}
+}
 
-			go func() {
-				defer func() {
-					mu.Lock()
-					delete(locks, invoice.ID.String())
-					mu.Unlock()
-				}()
+func (s *Service) completeInvoice(ctx context.Context, invoice *models.Invoice) {
+	const maxAttempts = 10
 
-				s.completeInvoice(ctx, invoice)
-			}()
+	// payout which is recorded is never sent again,
+	// e.g. if invoice status is not updated after transfer
+	payoutTransactions, err := s.storage.ListPayoutTransactions(ctx, []uuid.UUID{invoice.ID})
+	if err != nil {
+		logger.Errorf("storage.ListPayoutTransactions: %v", err)
+		return
+	}
+
+	if len(payoutTransactions) > 0 {
+		invoice.Status = invoice.PaidStatus()
+		_, err = s.storage.UpdateInvoice(ctx, invoice, storage.InvoiceUpdate{
+			Reason: fmt.Sprintf("payout is already sent in transaction %s", payoutTransactions[len(payoutTransactions)-1].TransactionHash),
+		})
+		if err != nil {
+			logger.Errorf("storage.UpdateInvoice: %v", err)
 		}
+
+		return
 	}
-}
 
-func (s *Service) completeInvoice(ctx context.Context, invoice *models.Invoice) {
-	defaultStep := 50000
+	transferCtx, cancel := context.WithTimeout(ctx, payoutTimeout)
+	defer cancel()
+
+	var transferErr error
+	for i := 0; i < maxAttempts; i++ {
+		// transfer which timed out may be sent, payout is left to operator
+		if transferCtx.Err() != nil {
+			break
+		}
+
+		var gas *payoutGas
+		gas, transferErr = s.suggestPayoutGas(transferCtx, invoice, i)
+		if transferErr != nil {
+			continue
+		}
 
-	for i := 0; i < 10; i++ {
-		gasLimit := uint64(50000 + defaultStep*i)
-		if invoice.GasLimit != nil {
-			gasLimit = uint64(*invoice.GasLimit)
+		// fee cap applies to gas price of operator too
+		transferErr = s.checkPayoutFee(transferCtx, invoice, gas.EstimatedFee)
+		if errors.Is(transferErr, ErrPayoutFeeExceeded) {
+			// bumped fee only grows, payout is left to operator
+			break
+		}
+		if transferErr != nil {
+			continue
 		}
 
-		_, err := s.cryptoServiceClient.Transfer(ctx, &crypto_service.TransferRequest{
+		var transferResp *crypto_service.TransferResponse
+		transferResp, transferErr = s.cryptoServiceClient.Transfer(transferCtx, &crypto_service.TransferRequest{
 			ClientId:  invoice.ClientID.String(),
 			InvoiceId: lo.ToPtr(invoice.ID.String()),
-			GasLimit:  lo.ToPtr(gasLimit),
+			GasLimit:  lo.ToPtr(gas.GasLimit),
+			GasPrice:  lo.ToPtr(gas.GasPrice),
 		})
-		if err != nil {
+		if transferErr != nil {
 			continue
 		}
 
-		invoice.Status = desc.InvoiceStatus_SUCCESS
-		_, err = s.storage.UpdateInvoice(ctx, invoice)
+		_, err := s.storage.CreatePayoutTransaction(ctx, &models.PayoutTransaction{
+			InvoiceID:       invoice.ID,
+			TransactionHash: transferResp.GetTransactionHash(),
+			GasLimit:        lo.ToPtr(gas.GasLimit),
+			GasPrice:        lo.ToPtr(gas.GasPrice),
+			EstimatedFee:    lo.ToPtr(gas.EstimatedFee),
+			Attempt:         i + 1,
+		})
+		if err != nil {
+			// funds are sent, invoice must be completed anyway
+			logger.Errorf("storage.CreatePayoutTransaction: %v", err)
+		}
+
+		invoice.Status = invoice.PaidStatus()
+		_, err = s.storage.UpdateInvoice(ctx, invoice, storage.InvoiceUpdate{
+			Reason: fmt.Sprintf("payout sent with gas limit %d, gas price %d", gas.GasLimit, gas.GasPrice),
+		})
 		if err != nil {
 			logger.Errorf("storage.UpdateInvoice: %v", err)
 			return
@@ -318,7 +869,9 @@ func (s *Service) completeInvoice(ctx context.Context, invoice *models.Invoice)
 	}
 
 	invoice.Status = desc.InvoiceStatus_MANUAL_CONTROL
-	_, err := s.storage.UpdateInvoice(ctx, invoice)
+	_, err = s.storage.UpdateInvoice(ctx, invoice, storage.InvoiceUpdate{
+		Reason: fmt.Sprintf("payout failed: %v", transferErr),
+	})
 	if err != nil {
 		logger.Errorf("storage.UpdateInvoice: %v", err)
 		return
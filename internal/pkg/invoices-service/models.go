@@ -1,38 +1,126 @@
 package invoicesservice
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/common"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/fx"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
 	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
 	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	minInvoiceTTL = time.Minute
+	maxInvoiceTTL = 7 * 24 * time.Hour
 )
 
 type CreateInvoiceInput struct {
-	ClientID       uuid.UUID
-	UsdCentsAmount int64
+	ClientID uuid.UUID
+	// Currency is ISO-4217 code, Amount is in its minor units
+	Currency          string
+	Amount            int64
+	IdempotencyKey    *string
+	ExternalReference *string
+	Description       *string
+	Metadata          map[string]string
+	TTL               *time.Duration
 }
 
 func CreateInvoiceInputFromRequest(req *desc.CreateInvoiceRequest) (*CreateInvoiceInput, error) {
 	err := validation.ValidateStruct(
 		req,
 		validation.Field(&req.ClientId, validation.Required, is.UUIDv4),
-		validation.Field(&req.UsdAmount, validation.Required),
+		validation.Field(&req.UsdAmount, validation.Min(0.0)),
+		validation.Field(&req.Amount, validation.Min(int64(0))),
+		validation.Field(&req.Currency, validation.By(validateCurrency)),
+		validation.Field(&req.IdempotencyKey, validation.NilOrNotEmpty, validation.Length(1, 255)),
+		validation.Field(&req.ExternalReference, validation.NilOrNotEmpty, validation.Length(1, 255)),
+		validation.Field(&req.Description, validation.Length(0, 1024)),
+		validation.Field(&req.Metadata, validation.Length(0, 50)),
+		validation.Field(&req.Ttl, validation.By(validateTTL)),
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if req.GetUsdAmount() == 0 && req.GetAmount() == 0 {
+		return nil, errors.New("amount: cannot be blank")
+	}
+
+	// usd_amount is always in USD, currency applies only to amount
+	if req.GetAmount() == 0 && req.GetCurrency() != "" && !strings.EqualFold(req.GetCurrency(), fx.USD) {
+		return nil, errors.New("currency: must be USD or empty when usd_amount is set")
+	}
+
+	var ttl *time.Duration
+	if req.Ttl != nil {
+		ttl = lo.ToPtr(req.GetTtl().AsDuration())
+	}
+
+	// usd_amount is kept for callers created before currencies were supported
+	currency, amount := fx.USD, fx.ToMinor(req.GetUsdAmount(), fx.USD)
+	if req.GetAmount() != 0 {
+		currency, amount = strings.ToUpper(req.GetCurrency()), req.GetAmount()
+		if currency == "" {
+			currency = fx.USD
+		}
+	}
+
 	return &CreateInvoiceInput{
-		ClientID:       uuid.MustParse(req.GetClientId()),
-		UsdCentsAmount: int64(req.GetUsdAmount() * 100),
+		ClientID:          uuid.MustParse(req.GetClientId()),
+		Currency:          currency,
+		Amount:            amount,
+		IdempotencyKey:    req.IdempotencyKey,
+		ExternalReference: req.ExternalReference,
+		Description:       req.Description,
+		Metadata:          req.GetMetadata(),
+		TTL:               ttl,
 	}, nil
 }
 
+func validateCurrency(value interface{}) error {
+	currency, _ := value.(string)
+	if currency == "" || fx.IsSupported(currency) {
+		return nil
+	}
+
+	return fmt.Errorf("currency %q is not supported", currency)
+}
+
+func validateTTL(value interface{}) error {
+	ttl, _ := value.(*durationpb.Duration)
+	if ttl == nil {
+		return nil
+	}
+
+	if err := ttl.CheckValid(); err != nil {
+		return err
+	}
+
+	if ttl.AsDuration() < minInvoiceTTL || ttl.AsDuration() > maxInvoiceTTL {
+		return fmt.Errorf("must be between %s and %s", minInvoiceTTL, maxInvoiceTTL)
+	}
+
+	return nil
+}
+
 type UpdateInvoiceInput struct {
 	InvoiceID     uuid.UUID
 	Chain         string
 	Token         string
 	PayerClientID *string
+	// Version is expected version of invoice, update is conditional if set
+	Version *int64
 }
 
 func UpdateInvoiceInputFromRequest(req *desc.UpdateInvoiceRequest) (*UpdateInvoiceInput, error) {
@@ -51,5 +139,198 @@ func UpdateInvoiceInputFromRequest(req *desc.UpdateInvoiceRequest) (*UpdateInvoi
 		Chain:         req.GetChain(),
 		Token:         req.GetToken(),
 		PayerClientID: req.PayerClientId,
+		Version:       req.Version,
+	}, nil
+}
+
+type CancelInvoiceInput struct {
+	InvoiceID uuid.UUID
+	Reason    string
+	Version   *int64
+}
+
+func CancelInvoiceInputFromRequest(req *desc.CancelInvoiceRequest) (*CancelInvoiceInput, error) {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.Id, validation.Required, is.UUIDv4),
+		validation.Field(&req.Reason, validation.Length(0, 1024)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CancelInvoiceInput{
+		InvoiceID: uuid.MustParse(req.GetId()),
+		Reason:    req.GetReason(),
+		Version:   req.Version,
+	}, nil
+}
+
+type RefundInvoiceInput struct {
+	InvoiceID   uuid.UUID
+	Address     string
+	Amount      *float64
+	AmountUnits *models.Units
+}
+
+func RefundInvoiceInputFromRequest(req *desc.RefundInvoiceRequest) (*RefundInvoiceInput, error) {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.InvoiceId, validation.Required, is.UUIDv4),
+		validation.Field(&req.Address, validation.Required),
+		validation.Field(&req.Amount, validation.NilOrNotEmpty, validation.Min(0.0).Exclusive()),
+		validation.Field(&req.AmountUnits, validation.NilOrNotEmpty, validation.By(validateUnits)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &RefundInvoiceInput{
+		InvoiceID: uuid.MustParse(req.GetInvoiceId()),
+		Address:   req.GetAddress(),
+		Amount:    req.Amount,
+	}
+
+	if req.AmountUnits != nil {
+		input.AmountUnits, _ = models.ParseUnits(req.GetAmountUnits())
+	}
+
+	return input, nil
+}
+
+func validateUnits(value interface{}) error {
+	units, _ := value.(*string)
+	if units == nil {
+		return nil
+	}
+
+	parsed, err := models.ParseUnits(*units)
+	if err != nil {
+		return err
+	}
+
+	if parsed.Int().Sign() <= 0 {
+		return errors.New("must be greater than 0")
+	}
+
+	return nil
+}
+
+type SetWebhookInput struct {
+	ClientID uuid.UUID
+	URL      string
+}
+
+func SetWebhookInputFromRequest(req *desc.SetWebhookRequest) (*SetWebhookInput, error) {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.ClientId, validation.Required, is.UUIDv4),
+		validation.Field(&req.Url, validation.Required, is.URL, validation.Length(1, 2048), validation.By(validateWebhookURL)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SetWebhookInput{
+		ClientID: uuid.MustParse(req.GetClientId()),
+		URL:      req.GetUrl(),
+	}, nil
+}
+
+// validateWebhookURL rejects URLs which point to internal network, address which host
+// resolves to is checked again when webhook is sent
+func validateWebhookURL(value interface{}) error {
+	rawURL, _ := value.(string)
+
+	webhookURL, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if webhookURL.Scheme != "https" {
+		return errors.New("must be https URL")
+	}
+
+	host := webhookURL.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !common.IsPublicIP(ip) {
+			return errors.New("must not point to internal address")
+		}
+
+		return nil
+	}
+
+	if common.IsInternalHost(host) {
+		return errors.New("must not point to internal host")
+	}
+
+	return nil
+}
+
+type RetryPayoutInput struct {
+	InvoiceID uuid.UUID
+	Operator  string
+	GasLimit  *uint64
+	GasPrice  *uint64
+	Note      string
+}
+
+func RetryPayoutInputFromRequest(req *desc.RetryPayoutRequest, operator string) (*RetryPayoutInput, error) {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.InvoiceId, validation.Required, is.UUIDv4),
+		validation.Field(&req.GasLimit, validation.NilOrNotEmpty),
+		validation.Field(&req.GasPrice, validation.NilOrNotEmpty),
+		validation.Field(&req.Note, validation.Length(0, 1024)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RetryPayoutInput{
+		InvoiceID: uuid.MustParse(req.GetInvoiceId()),
+		Operator:  operator,
+		GasLimit:  req.GasLimit,
+		GasPrice:  req.GasPrice,
+		Note:      req.GetNote(),
 	}, nil
 }
+
+type MarkResolvedInput struct {
+	InvoiceID       uuid.UUID
+	Operator        string
+	Note            string
+	Status          desc.InvoiceStatus
+	TransactionHash *string
+}
+
+func MarkResolvedInputFromRequest(req *desc.MarkResolvedRequest, operator string) (*MarkResolvedInput, error) {
+	err := validation.ValidateStruct(
+		req,
+		validation.Field(&req.InvoiceId, validation.Required, is.UUIDv4),
+		validation.Field(&req.Note, validation.Required, validation.Length(1, 1024)),
+		validation.Field(&req.Status, validation.Required, validation.In(
+			desc.InvoiceStatus_SUCCESS,
+			desc.InvoiceStatus_OVERPAID,
+			desc.InvoiceStatus_FAILED,
+		)),
+		validation.Field(&req.TransactionHash, validation.NilOrNotEmpty),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MarkResolvedInput{
+		InvoiceID:       uuid.MustParse(req.GetInvoiceId()),
+		Operator:        operator,
+		Note:            req.GetNote(),
+		Status:          req.GetStatus(),
+		TransactionHash: req.TransactionHash,
+	}, nil
+}
+
+type ListInvoicesOutput struct {
+	Invoices      []*models.Invoice
+	NextPageToken string
+	TotalCount    *uint64
+}
@@ -0,0 +1,44 @@
+package invoicesservice
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/storage"
+)
+
+// encodePageToken returns opaque token pointing after the last invoice of the page
+func encodePageToken(sort storage.InvoiceSort, lastInvoice *models.Invoice) (string, error) {
+	body, err := json.Marshal(storage.InvoiceCursor{
+		Sort:           sort,
+		CreatedAt:      lastInvoice.CreatedAt,
+		UsdCentsAmount: lastInvoice.UsdCentsAmount,
+		ID:             lastInvoice.ID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(body), nil
+}
+
+// decodePageToken rejects token issued for another sort order
+func decodePageToken(sort storage.InvoiceSort, pageToken string) (*storage.InvoiceCursor, error) {
+	body, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	cursor := new(storage.InvoiceCursor)
+	if err = json.Unmarshal(body, cursor); err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	if cursor.Sort != sort {
+		return nil, ErrInvalidPageToken
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,121 @@
+package invoicesservice
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/fidesy-pay/invoices-service/internal/config"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	crypto_service "github.com/fidesy-pay/invoices-service/pkg/crypto-service"
+	external_api "github.com/fidesy-pay/invoices-service/pkg/external-api"
+	"github.com/fidesy/sdk/common/logger"
+	"github.com/samber/lo"
+)
+
+const (
+	defaultGasLimit = 50000
+	gasLimitStep    = 50000
+	// gasBump raises gas price and fee on every retry, replacing
+	// pending transaction requires at least 10% higher price
+	gasBump = 0.125
+)
+
+type payoutGas struct {
+	GasLimit uint64
+	GasPrice uint64
+	// EstimatedFee is fee of transfer at GasPrice, actual fee is not known
+	// until transaction is mined and is at most GasLimit * GasPrice
+	EstimatedFee uint64
+}
+
+// suggestPayoutGas returns gas of payout attempt starting from 0: gas limit is raised by
+// gasLimitStep unless it is set on invoice, suggested gas price is bumped by gasBump
+// unless gas price is set on invoice. Fee is estimated at the same gas price transfer is sent with
+func (s *Service) suggestPayoutGas(ctx context.Context, invoice *models.Invoice, attempt int) (*payoutGas, error) {
+	gasLimit := uint64(defaultGasLimit + gasLimitStep*attempt)
+	if invoice.GasLimit != nil {
+		gasLimit = uint64(*invoice.GasLimit)
+	}
+
+	if invoice.GasPrice != nil {
+		return &payoutGas{
+			GasLimit: gasLimit,
+			GasPrice: *invoice.GasPrice,
+			// fee is not suggested for gas price of operator, so its upper bound is used
+			EstimatedFee: gasLimit * *invoice.GasPrice,
+		}, nil
+	}
+
+	gasPriceResp, err := s.cryptoServiceClient.SuggestGasPrice(ctx, &crypto_service.SuggestGasPriceRequest{
+		Chain: invoice.Chain,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cryptoServiceClient.SuggestGasPrice: %w", err)
+	}
+
+	feeResp, err := s.cryptoServiceClient.SuggestFee(ctx, &crypto_service.SuggestFeeRequest{
+		Chain:    invoice.Chain,
+		GasLimit: lo.ToPtr(gasLimit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cryptoServiceClient.SuggestFee: %w", err)
+	}
+
+	var (
+		bump     = math.Pow(1+gasBump, float64(attempt))
+		gasPrice = uint64(float64(gasPriceResp.GetPrice()) * bump)
+	)
+
+	return &payoutGas{
+		GasLimit:     gasLimit,
+		GasPrice:     gasPrice,
+		EstimatedFee: feeAtGasPrice(feeResp.GetFee(), gasPriceResp.GetPrice(), gasPrice),
+	}, nil
+}
+
+// feeAtGasPrice scales fee suggested at suggestedGasPrice to gasPrice
+func feeAtGasPrice(fee, suggestedGasPrice, gasPrice uint64) uint64 {
+	if suggestedGasPrice == 0 {
+		return fee
+	}
+
+	scaled := new(big.Int).Mul(new(big.Int).SetUint64(fee), new(big.Int).SetUint64(gasPrice))
+	scaled.Quo(scaled, new(big.Int).SetUint64(suggestedGasPrice))
+
+	return scaled.Uint64()
+}
+
+// checkPayoutFee returns ErrPayoutFeeExceeded if fee in USD is above
+// payout-max-fee-ratio of invoice amount
+func (s *Service) checkPayoutFee(ctx context.Context, invoice *models.Invoice, fee uint64) error {
+	maxFeeRatio := config.Get(config.PayoutMaxFeeRatio).(float64)
+	if maxFeeRatio == 0 {
+		return nil
+	}
+
+	nativeToken, ok := s.tokenRegistry.Native(invoice.Chain)
+	if !ok {
+		logger.Info(fmt.Sprintf("payout fee of invoice %s is not checked: native token of chain %q is unknown", invoice.ID.String(), invoice.Chain))
+		return nil
+	}
+
+	priceResp, err := s.externalAPI.GetPrice(ctx, &external_api.GetPriceRequest{
+		Symbol: nativeToken.Symbol,
+	})
+	if err != nil {
+		return fmt.Errorf("externalAPI.GetPrice: %w", err)
+	}
+
+	var (
+		feeUSD    = nativeToken.FromBaseUnits(new(big.Int).SetUint64(fee)) * priceResp.GetPriceUsd()
+		maxFeeUSD = float64(invoice.UsdCentsAmount) / 100 * maxFeeRatio
+	)
+
+	if feeUSD > maxFeeUSD {
+		return fmt.Errorf("%w: fee = %.2f USD, max = %.2f USD", ErrPayoutFeeExceeded, feeUSD, maxFeeUSD)
+	}
+
+	return nil
+}
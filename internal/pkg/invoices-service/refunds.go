@@ -0,0 +1,255 @@
+package invoicesservice
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/storage"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/tokens"
+	crypto_service "github.com/fidesy-pay/invoices-service/pkg/crypto-service"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/fidesy/sdk/common/logger"
+	"github.com/samber/lo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxRefundAttempts = 10
+
+// refundableStatuses lists statuses of invoices which received funds can be returned to payer.
+// Transfer of crypto service to address sends funds only from client wallet, so funds
+// left on wallet of cancelled and expired invoices can not be refunded
+var refundableStatuses = []desc.InvoiceStatus{
+	desc.InvoiceStatus_SUCCESS,
+	desc.InvoiceStatus_OVERPAID,
+}
+
+func (s *Service) RefundInvoice(ctx context.Context, input *RefundInvoiceInput) (*models.Refund, error) {
+	invoice, err := s.getInvoice(ctx, input.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lo.Contains(refundableStatuses, invoice.Status) {
+		return nil, fmt.Errorf("%w: status = %s", ErrInvoiceNotRefundable, invoice.Status.String())
+	}
+
+	token, ok := s.tokenRegistry.Get(invoice.Chain, invoice.Token)
+	if !ok {
+		return nil, fmt.Errorf("%w: chain = %q, token = %q", ErrTokenNotSupported, invoice.Chain, invoice.Token)
+	}
+
+	// transfer of crypto service has no token, it sends native token of chain
+	if !token.IsNative() {
+		return nil, fmt.Errorf("%w: chain = %q, token = %q", ErrRefundTokenNotSupported, invoice.Chain, invoice.Token)
+	}
+
+	received := receivedUnits(invoice, token)
+
+	amount := received
+	if invoice.Status == desc.InvoiceStatus_OVERPAID {
+		amount = overpaidUnits(invoice, token)
+	}
+	switch {
+	case input.AmountUnits != nil:
+		amount = input.AmountUnits.Int()
+	case input.Amount != nil:
+		amount = token.ToBaseUnits(*input.Amount)
+	}
+
+	// transfer amount is float, refund records exactly the units which are sent
+	amount = transferableUnits(token, amount)
+	if amount.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: requested = %s", models.ErrRefundAmountExceeded, token.Format(amount))
+	}
+
+	// refunds of invoice are summed and checked against received amount under
+	// lock of invoice, so that concurrent refunds never exceed it
+	refund, err := s.storage.CreateRefund(
+		ctx,
+		&models.Refund{
+			InvoiceID:   invoice.ID,
+			Amount:      token.FromBaseUnits(amount),
+			AmountUnits: models.NewUnits(amount),
+			Address:     strings.ToLower(input.Address),
+			Status:      desc.RefundStatus_REFUND_PENDING,
+		},
+		storage.RefundLimit{
+			InvoiceVersion: invoice.Version,
+			Received:       received,
+			Decimals:       token.Decimals,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage.CreateRefund: %w", err)
+	}
+
+	return refund, nil
+}
+
+// receivedUnits returns amount received by invoice in base units of token
+func receivedUnits(invoice *models.Invoice, token tokens.Token) *big.Int {
+	if invoice.ReceivedAmountUnits != nil {
+		return invoice.ReceivedAmountUnits.Int()
+	}
+
+	// invoices paid before exact amounts were recorded
+	if invoice.ReceivedAmount != nil {
+		return token.ToBaseUnits(*invoice.ReceivedAmount)
+	}
+
+	// invoices paid before received amount was recorded
+	if invoice.Status == desc.InvoiceStatus_SUCCESS || invoice.Status == desc.InvoiceStatus_OVERPAID {
+		return token.ToBaseUnits(lo.FromPtr(invoice.TokenAmount))
+	}
+
+	return new(big.Int)
+}
+
+// transferableUnits returns the greatest amount not above units which is passed
+// to transfer of crypto service without loss, as transfer takes float amount of token
+func transferableUnits(token tokens.Token, units *big.Int) *big.Int {
+	amount := token.FromBaseUnits(units)
+	for amount > 0 && token.ToBaseUnits(amount).Cmp(units) > 0 {
+		amount = math.Nextafter(amount, 0)
+	}
+
+	return token.ToBaseUnits(amount)
+}
+
+// overpaidUnits returns amount invoice is overpaid by in base units of token
+func overpaidUnits(invoice *models.Invoice, token tokens.Token) *big.Int {
+	if invoice.OverpaidAmountUnits != nil {
+		return invoice.OverpaidAmountUnits.Int()
+	}
+
+	return token.ToBaseUnits(lo.FromPtr(invoice.OverpaidAmount))
+}
+
+func (s *Service) refundWorker(ctx context.Context) {
+	ctx = context.WithValue(ctx, "skip_span", true)
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			go s.executeRefunds(ctx)
+		}
+	}
+}
+
+func (s *Service) executeRefunds(ctx context.Context) {
+	refunds, err := s.storage.ClaimRefunds(
+		ctx,
+		[]desc.RefundStatus{desc.RefundStatus_REFUND_PENDING},
+		s.workerID,
+		transferLease,
+		100,
+	)
+	if err != nil {
+		logger.Errorf("refundWorker: storage.ClaimRefunds: %v", err)
+		return
+	}
+
+	for _, refund := range refunds {
+		refund := refund
+
+		go func() {
+			defer func() {
+				err := s.storage.ReleaseRefund(ctx, refund.ID, s.workerID)
+				if err != nil {
+					logger.Errorf("refundWorker: storage.ReleaseRefund: %v", err)
+				}
+			}()
+
+			s.executeRefund(ctx, refund)
+		}()
+	}
+}
+
+// executeRefund makes single transfer attempt. Refund is moved to REFUND_SENDING before
+// transfer, so that it is never sent twice: only refunds which transfer is rejected
+// are retried on next tick, refunds with unknown transfer result are left to operator
+func (s *Service) executeRefund(ctx context.Context, refund *models.Refund) {
+	invoice, err := s.getInvoice(ctx, refund.InvoiceID)
+	if err != nil {
+		logger.Errorf("executeRefund: getInvoice: %v", err)
+		return
+	}
+
+	token, ok := s.tokenRegistry.Get(invoice.Chain, invoice.Token)
+	if !ok {
+		logger.Errorf("executeRefund: %v", fmt.Errorf("%w: chain = %q, token = %q", ErrTokenNotSupported, invoice.Chain, invoice.Token))
+		return
+	}
+
+	amount := refund.Amount
+	if refund.AmountUnits != nil {
+		amount = token.FromBaseUnits(refund.AmountUnits.Int())
+	}
+
+	refund.Attempts++
+	refund.Status = desc.RefundStatus_REFUND_SENDING
+
+	refund, err = s.storage.UpdateRefund(ctx, refund)
+	if err != nil {
+		logger.Errorf("executeRefund: storage.UpdateRefund: %v", err)
+		return
+	}
+
+	transferResp, err := s.cryptoServiceClient.Transfer(ctx, &crypto_service.TransferRequest{
+		ClientId:        invoice.ClientID.String(),
+		ReceiverAddress: lo.ToPtr(refund.Address),
+		Amount:          amount,
+		Chain:           invoice.Chain,
+	})
+	switch {
+	case err == nil:
+		refund.Status = desc.RefundStatus_REFUND_SUCCESS
+		refund.TransactionHash = lo.ToPtr(transferResp.GetTransactionHash())
+	case transferRejected(err):
+		logger.Errorf("executeRefund: cryptoServiceClient.Transfer: %v", err)
+
+		refund.Status = desc.RefundStatus_REFUND_PENDING
+		if refund.Attempts >= maxRefundAttempts {
+			refund.Status = desc.RefundStatus_REFUND_FAILED
+		}
+	default:
+		logger.Errorf("executeRefund: %v", fmt.Errorf(
+			"transfer result of refund %s is unknown, refund is left in REFUND_SENDING: %w", refund.ID.String(), err,
+		))
+		return
+	}
+
+	_, err = s.storage.UpdateRefund(ctx, refund)
+	if err != nil {
+		logger.Errorf("storage.UpdateRefund: %v", err)
+	}
+}
+
+// transferRejected reports whether transfer error means that funds are definitely not sent,
+// timeouts and unavailability do not tell whether transfer is sent
+func transferRejected(err error) bool {
+	switch status.Code(err) {
+	case codes.InvalidArgument,
+		codes.FailedPrecondition,
+		codes.NotFound,
+		codes.PermissionDenied,
+		codes.Unauthenticated,
+		codes.ResourceExhausted,
+		codes.OutOfRange,
+		codes.Unimplemented:
+		return true
+	}
+
+	return false
+}
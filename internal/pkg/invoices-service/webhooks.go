@@ -0,0 +1,314 @@
+package invoicesservice
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/common"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/storage"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/fidesy/sdk/common/logger"
+	"github.com/fidesy/sdk/common/postgres"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+const (
+	maxWebhookAttempts    = 10
+	webhookInitialBackoff = 30 * time.Second
+	webhookMaxBackoff     = 6 * time.Hour
+	// webhookLease covers single delivery attempt, it is longer than timeout of webhook request
+	webhookLease = time.Minute
+
+	webhookSignatureHeader = "X-Signature"
+	webhookTimestampHeader = "X-Webhook-Timestamp"
+	webhookIDHeader        = "X-Webhook-ID"
+)
+
+// NewWebhookHTTPClient returns client which connects only to internet addresses,
+// so that webhook URL which host resolves to internal address is never requested
+func NewWebhookHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		// address is checked after host is resolved, right before connection
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			ip := net.ParseIP(host)
+			if ip == nil || !common.IsPublicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrWebhookAddressNotAllowed, address)
+			}
+
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// proxy would be dialed instead of webhook host
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if req.URL.Scheme != "https" {
+				return fmt.Errorf("%w: redirect to %s", ErrWebhookAddressNotAllowed, req.URL.Redacted())
+			}
+
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+
+			return nil
+		},
+	}
+}
+
+// SetWebhook sets client webhook URL and returns newly generated signing secret
+func (s *Service) SetWebhook(ctx context.Context, input *SetWebhookInput) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("rand.Read: %w", err)
+	}
+
+	webhook, err := s.storage.SaveWebhook(ctx, &models.Webhook{
+		ClientID: input.ClientID,
+		URL:      input.URL,
+		Secret:   hex.EncodeToString(secret),
+	})
+	if err != nil {
+		return "", fmt.Errorf("storage.SaveWebhook: %w", err)
+	}
+
+	return webhook.Secret, nil
+}
+
+func (s *Service) ListWebhookDeliveries(ctx context.Context, req *desc.ListWebhookDeliveriesRequest) ([]*models.WebhookDelivery, error) {
+	var err error
+
+	reqFilter := req.GetFilter()
+
+	filter := storage.ListWebhookDeliveriesFilter{}
+	if len(reqFilter.GetClientIdIn()) > 0 {
+		filter.ClientIDIn, err = common.ConvertToUUIDs(reqFilter.GetClientIdIn())
+		if err != nil {
+			return nil, fmt.Errorf("common.ConvertToUUIDs: %w", err)
+		}
+	}
+
+	if len(reqFilter.GetInvoiceIdIn()) > 0 {
+		filter.InvoiceIDIn, err = common.ConvertToUUIDs(reqFilter.GetInvoiceIdIn())
+		if err != nil {
+			return nil, fmt.Errorf("common.ConvertToUUIDs: %w", err)
+		}
+	}
+
+	if len(reqFilter.GetStatusIn()) > 0 {
+		filter.StatusIn = reqFilter.GetStatusIn()
+	}
+
+	deliveries, err := s.storage.ListWebhookDeliveries(ctx, filter, postgres.NewPagination(req.GetPage(), req.GetPerPage()))
+	if err != nil {
+		return nil, fmt.Errorf("storage.ListWebhookDeliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (s *Service) RedeliverWebhook(ctx context.Context, deliveryID uuid.UUID) (*models.WebhookDelivery, error) {
+	deliveries, err := s.storage.ListWebhookDeliveries(
+		ctx,
+		storage.ListWebhookDeliveriesFilter{
+			IDIn: []uuid.UUID{deliveryID},
+		},
+		postgres.NewPagination(1, 1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("storage.ListWebhookDeliveries: %w", err)
+	}
+
+	if len(deliveries) == 0 {
+		return nil, ErrWebhookDeliveryNotFoundByID(deliveryID)
+	}
+
+	delivery := deliveries[0]
+	delivery.Status = desc.WebhookDeliveryStatus_WEBHOOK_PENDING
+	delivery.Attempts = 0
+	delivery.NextRetryAt = time.Now()
+
+	delivery, err = s.storage.UpdateWebhookDelivery(ctx, delivery)
+	if err != nil {
+		return nil, fmt.Errorf("storage.UpdateWebhookDelivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+func (s *Service) webhookWorker(ctx context.Context) {
+	ctx = context.WithValue(ctx, "skip_span", true)
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			go s.deliverWebhooks(ctx)
+		}
+	}
+}
+
+// deliverWebhooks claims deliveries due to be sent, delivery claimed by one
+// instance is not claimed by others until it is released or its lease expires
+func (s *Service) deliverWebhooks(ctx context.Context) {
+	deliveries, err := s.storage.ClaimWebhookDeliveries(
+		ctx,
+		storage.ListWebhookDeliveriesFilter{
+			StatusIn:       []desc.WebhookDeliveryStatus{desc.WebhookDeliveryStatus_WEBHOOK_PENDING},
+			NextRetryAtLte: lo.ToPtr(time.Now()),
+		},
+		s.workerID,
+		webhookLease,
+		100,
+	)
+	if err != nil {
+		logger.Errorf("webhookWorker: storage.ClaimWebhookDeliveries: %v", err)
+		return
+	}
+
+	if len(deliveries) == 0 {
+		return
+	}
+
+	webhooks, err := s.storage.ListWebhooks(ctx, lo.Uniq(lo.Map(deliveries, func(delivery *models.WebhookDelivery, _ int) uuid.UUID {
+		return delivery.ClientID
+	})))
+	if err != nil {
+		logger.Errorf("webhookWorker: storage.ListWebhooks: %v", err)
+		s.releaseWebhookDeliveries(ctx, deliveries)
+		return
+	}
+
+	secrets := lo.SliceToMap(webhooks, func(webhook *models.Webhook) (uuid.UUID, string) {
+		return webhook.ClientID, webhook.Secret
+	})
+
+	for _, delivery := range deliveries {
+		delivery := delivery
+
+		go func() {
+			defer s.releaseWebhookDeliveries(ctx, []*models.WebhookDelivery{delivery})
+
+			s.deliverWebhook(ctx, delivery, secrets[delivery.ClientID])
+		}()
+	}
+}
+
+func (s *Service) releaseWebhookDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) {
+	for _, delivery := range deliveries {
+		err := s.storage.ReleaseWebhookDelivery(ctx, delivery.ID, s.workerID)
+		if err != nil {
+			logger.Errorf("storage.ReleaseWebhookDelivery: %v", err)
+		}
+	}
+}
+
+// deliverWebhook makes single delivery attempt, failed attempts are retried with exponential backoff
+func (s *Service) deliverWebhook(ctx context.Context, delivery *models.WebhookDelivery, secret string) {
+	delivery.Attempts++
+
+	responseCode, err := s.postWebhook(ctx, delivery, secret)
+	if responseCode != 0 {
+		delivery.ResponseCode = lo.ToPtr(responseCode)
+	}
+
+	now := time.Now()
+	if err == nil {
+		delivery.Status = desc.WebhookDeliveryStatus_WEBHOOK_DELIVERED
+		delivery.DeliveredAt = lo.ToPtr(now)
+		delivery.LastError = nil
+	} else {
+		delivery.LastError = lo.ToPtr(err.Error())
+		delivery.NextRetryAt = now.Add(webhookBackoff(delivery.Attempts))
+
+		if delivery.Attempts >= maxWebhookAttempts {
+			delivery.Status = desc.WebhookDeliveryStatus_WEBHOOK_FAILED
+		}
+	}
+
+	_, err = s.storage.UpdateWebhookDelivery(ctx, delivery)
+	if err != nil {
+		logger.Errorf("storage.UpdateWebhookDelivery: %v", err)
+	}
+}
+
+func (s *Service) postWebhook(ctx context.Context, delivery *models.WebhookDelivery, secret string) (int, error) {
+	if secret == "" {
+		return 0, fmt.Errorf("webhook not found by client id = %q", delivery.ClientID.String())
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookIDHeader, delivery.ID.String())
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, "sha256="+signWebhook(secret, timestamp, delivery.Payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("httpClient.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// signWebhook signs timestamp and payload so receiver can verify sender and reject replays
+func signWebhook(secret, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func webhookBackoff(attempts int) time.Duration {
+	backoff := time.Duration(float64(webhookInitialBackoff) * math.Pow(2, float64(attempts-1)))
+	if backoff > webhookMaxBackoff {
+		return webhookMaxBackoff
+	}
+
+	return backoff
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AuditLogEntry is call of admin API
+type AuditLogEntry struct {
+	ID        int64      `db:"id" json:"id"`
+	Operator  string     `db:"operator" json:"operator"`
+	Action    string     `db:"action" json:"action"`
+	InvoiceID *uuid.UUID `db:"invoice_id" json:"invoice_id"`
+	Note      string     `db:"note" json:"note"`
+	Request   string     `db:"request" json:"request"`
+	Error     *string    `db:"error" json:"error"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+}
+
+func (e *AuditLogEntry) TableName() string {
+	return "admin_audit_log"
+}
+
+func (e *AuditLogEntry) ToInsertMap() map[string]interface{} {
+	return map[string]interface{}{
+		"operator":   e.Operator,
+		"action":     e.Action,
+		"invoice_id": e.InvoiceID,
+		"note":       e.Note,
+		"request":    e.Request,
+		"error":      e.Error,
+	}
+}
+
+func (e *AuditLogEntry) Proto() *desc.AuditLogEntry {
+	if e == nil {
+		return nil
+	}
+
+	entry := &desc.AuditLogEntry{
+		Operator:  e.Operator,
+		Action:    e.Action,
+		Note:      e.Note,
+		Request:   e.Request,
+		CreatedAt: timestamppb.New(e.CreatedAt),
+	}
+
+	if e.InvoiceID != nil {
+		entry.InvoiceId = e.InvoiceID.String()
+	}
+
+	if e.Error != nil {
+		entry.Error = *e.Error
+	}
+
+	return entry
+}
+
+func AuditLogEntriesToProto(entries []*AuditLogEntry) []*desc.AuditLogEntry {
+	if entries == nil {
+		return []*desc.AuditLogEntry{}
+	}
+
+	result := make([]*desc.AuditLogEntry, len(entries))
+	for i := 0; i < len(entries); i++ {
+		result[i] = entries[i].Proto()
+	}
+
+	return result
+}
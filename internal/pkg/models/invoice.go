@@ -1,44 +1,83 @@
 package models
 
 import (
+	"errors"
 	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"time"
 )
 
 type Invoice struct {
-	ID             uuid.UUID          `db:"id" json:"id"`
-	ClientID       uuid.UUID          `db:"client_id" json:"client_id"`
-	UsdCentsAmount int64              `db:"usd_cents_amount" json:"usd_cents_amount"`
-	TokenAmount    *float64           `db:"token_amount" json:"token_amount"`
-	Chain          string             `db:"chain" json:"chain"`
-	Token          string             `db:"token" json:"token"`
-	Status         desc.InvoiceStatus `db:"status" json:"status"`
-	Address        string             `db:"address" json:"address"`
-	CreatedAt      time.Time          `db:"created_at" json:"created_at"`
-	PayerClientID  *string            `db:"payer_client_id" json:"payer_client_id"`
-	GasLimit       *int               `db:"gas_limit" json:"gas_limit"`
+	ID                 uuid.UUID          `db:"id" json:"id"`
+	ClientID           uuid.UUID          `db:"client_id" json:"client_id"`
+	UsdCentsAmount     int64              `db:"usd_cents_amount" json:"usd_cents_amount"`
+	TokenAmount        *float64           `db:"token_amount" json:"token_amount"`
+	Chain              string             `db:"chain" json:"chain"`
+	Token              string             `db:"token" json:"token"`
+	Status             desc.InvoiceStatus `db:"status" json:"status"`
+	Address            string             `db:"address" json:"address"`
+	CreatedAt          time.Time          `db:"created_at" json:"created_at"`
+	PayerClientID      *string            `db:"payer_client_id" json:"payer_client_id"`
+	GasLimit           *int               `db:"gas_limit" json:"gas_limit"`
+	CancellationReason *string            `db:"cancellation_reason" json:"cancellation_reason"`
+	IdempotencyKey     *string            `db:"idempotency_key" json:"idempotency_key"`
+	ExternalReference  *string            `db:"external_reference" json:"external_reference"`
+	Description        *string            `db:"description" json:"description"`
+	Metadata           map[string]string  `db:"metadata" json:"metadata"`
+	ExpiresAt          time.Time          `db:"expires_at" json:"expires_at"`
+	ReceivedAmount     *float64           `db:"received_amount" json:"received_amount"`
+	OverpaidAmount     *float64           `db:"overpaid_amount" json:"overpaid_amount"`
+	Currency           string             `db:"currency" json:"currency"`
+	Amount             int64              `db:"amount" json:"amount"`
+	PriceUsd           *float64           `db:"price_usd" json:"price_usd"`
+	QuoteExpiresAt     *time.Time         `db:"quote_expires_at" json:"quote_expires_at"`
+	Version            int64              `db:"version" json:"version"`
+	GasPrice           *uint64            `db:"gas_price" json:"gas_price"`
+	// TokenAmountUnits, ReceivedAmountUnits and OverpaidAmountUnits are exact amounts
+	// in base units of token, float amounts are kept for compatibility
+	TokenAmountUnits    *Units               `db:"token_amount_units" json:"token_amount_units"`
+	ReceivedAmountUnits *Units               `db:"received_amount_units" json:"received_amount_units"`
+	OverpaidAmountUnits *Units               `db:"overpaid_amount_units" json:"overpaid_amount_units"`
+	TokenDecimals       *int32               `db:"token_decimals" json:"token_decimals"`
+	PayoutTransactions  []*PayoutTransaction `db:"-" json:"payout_transactions"`
 }
 
+// ErrVersionConflict is returned when invoice is changed after it was read,
+// update can be retried with fresh invoice
+var ErrVersionConflict = errors.New("invoice is modified concurrently")
+
 func (i *Invoice) TableName() string {
 	return "invoices"
 }
 
 func (i *Invoice) ToInsertMap() map[string]interface{} {
 	return map[string]interface{}{
-		"client_id":        i.ClientID.String(),
-		"usd_cents_amount": i.UsdCentsAmount,
-		"token_amount":     i.TokenAmount,
-		"chain":            i.Chain,
-		"token":            i.Token,
-		"status":           i.Status,
-		"address":          i.Address,
+		"client_id":          i.ClientID.String(),
+		"usd_cents_amount":   i.UsdCentsAmount,
+		"token_amount":       i.TokenAmount,
+		"chain":              i.Chain,
+		"token":              i.Token,
+		"status":             i.Status,
+		"address":            i.Address,
+		"idempotency_key":    i.IdempotencyKey,
+		"external_reference": i.ExternalReference,
+		"description":        i.Description,
+		"metadata":           i.Metadata,
+		"expires_at":         i.ExpiresAt,
+		"currency":           i.Currency,
+		"amount":             i.Amount,
 	}
 }
 
+// ToUpdateMap returns columns of invoice which are set, nil and empty fields are left unchanged.
+// Version is always incremented: storage.UpdateInvoice applies the update only if row
+// still has Version the invoice was read with, so that concurrent changes are not overwritten
 func (i *Invoice) ToUpdateMap() map[string]interface{} {
-	updateData := map[string]interface{}{}
+	updateData := map[string]interface{}{
+		"version": i.Version + 1,
+	}
 
 	if i.Chain != "" {
 		updateData["chain"] = i.Chain
@@ -52,6 +91,14 @@ func (i *Invoice) ToUpdateMap() map[string]interface{} {
 		updateData["token_amount"] = *i.TokenAmount
 	}
 
+	if i.PriceUsd != nil {
+		updateData["price_usd"] = *i.PriceUsd
+	}
+
+	if i.QuoteExpiresAt != nil {
+		updateData["quote_expires_at"] = *i.QuoteExpiresAt
+	}
+
 	if i.Address != "" {
 		updateData["address"] = i.Address
 	}
@@ -64,9 +111,69 @@ func (i *Invoice) ToUpdateMap() map[string]interface{} {
 		updateData["payer_client_id"] = i.PayerClientID
 	}
 
+	if i.CancellationReason != nil {
+		updateData["cancellation_reason"] = i.CancellationReason
+	}
+
+	if i.ReceivedAmount != nil {
+		updateData["received_amount"] = *i.ReceivedAmount
+	}
+
+	if i.OverpaidAmount != nil {
+		updateData["overpaid_amount"] = *i.OverpaidAmount
+	}
+
+	if i.GasLimit != nil {
+		updateData["gas_limit"] = *i.GasLimit
+	}
+
+	if i.GasPrice != nil {
+		updateData["gas_price"] = *i.GasPrice
+	}
+
+	if i.TokenAmountUnits != nil {
+		updateData["token_amount_units"] = i.TokenAmountUnits
+	}
+
+	if i.ReceivedAmountUnits != nil {
+		updateData["received_amount_units"] = i.ReceivedAmountUnits
+	}
+
+	if i.OverpaidAmountUnits != nil {
+		updateData["overpaid_amount_units"] = i.OverpaidAmountUnits
+	}
+
+	if i.TokenDecimals != nil {
+		updateData["token_decimals"] = *i.TokenDecimals
+	}
+
 	return updateData
 }
 
+// QuoteExpired reports whether token amount must be re-priced before accepting payment
+func (i *Invoice) QuoteExpired(now time.Time) bool {
+	return i.QuoteExpiresAt != nil && now.After(*i.QuoteExpiresAt)
+}
+
+// IsFinal reports whether invoice status can not change anymore
+func (i *Invoice) IsFinal() bool {
+	return len(lo.Without(statusTransitions[i.Status], i.Status)) == 0
+}
+
+// PaidStatus returns final status of invoice which funds are forwarded to client
+func (i *Invoice) PaidStatus() desc.InvoiceStatus {
+	if i.OverpaidAmountUnits != nil && i.OverpaidAmountUnits.Int().Sign() > 0 {
+		return desc.InvoiceStatus_OVERPAID
+	}
+
+	// invoices paid before exact amounts were recorded
+	if i.OverpaidAmountUnits == nil && i.OverpaidAmount != nil && *i.OverpaidAmount > 0 {
+		return desc.InvoiceStatus_OVERPAID
+	}
+
+	return desc.InvoiceStatus_SUCCESS
+}
+
 func (i *Invoice) Proto() *desc.Invoice {
 	if i == nil {
 		return nil
@@ -81,16 +188,65 @@ func (i *Invoice) Proto() *desc.Invoice {
 		Status:    i.Status,
 		Address:   i.Address,
 		CreatedAt: timestamppb.New(i.CreatedAt),
+		ExpiresAt: timestamppb.New(i.ExpiresAt),
+		Currency:  i.Currency,
+		Amount:    i.Amount,
+		Version:   i.Version,
+
+		UsdCentsAmount:      i.UsdCentsAmount,
+		TokenAmountUnits:    i.TokenAmountUnits.String(),
+		ReceivedAmountUnits: i.ReceivedAmountUnits.String(),
+		OverpaidAmountUnits: i.OverpaidAmountUnits.String(),
+	}
+
+	if i.TokenDecimals != nil {
+		invoice.TokenDecimals = *i.TokenDecimals
 	}
 
 	if i.TokenAmount != nil {
 		invoice.TokenAmount = *i.TokenAmount
 	}
 
+	if i.PriceUsd != nil {
+		invoice.PriceUsd = *i.PriceUsd
+	}
+
+	if i.QuoteExpiresAt != nil {
+		invoice.QuoteExpiresAt = timestamppb.New(*i.QuoteExpiresAt)
+	}
+
 	if i.PayerClientID != nil {
 		invoice.PayerClientId = *i.PayerClientID
 	}
 
+	if i.ReceivedAmount != nil {
+		invoice.ReceivedAmount = *i.ReceivedAmount
+	}
+
+	if i.OverpaidAmount != nil {
+		invoice.OverpaidAmount = *i.OverpaidAmount
+	}
+
+	if i.CancellationReason != nil {
+		invoice.CancellationReason = *i.CancellationReason
+	}
+
+	if i.ExternalReference != nil {
+		invoice.ExternalReference = *i.ExternalReference
+	}
+
+	if i.Description != nil {
+		invoice.Description = *i.Description
+	}
+
+	if len(i.Metadata) > 0 {
+		invoice.Metadata = i.Metadata
+	}
+
+	if len(i.PayoutTransactions) > 0 {
+		invoice.PayoutTransactions = PayoutTransactionsToProto(i.PayoutTransactions)
+	}
+
 	return invoice
 }
 
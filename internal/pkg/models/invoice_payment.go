@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// InvoicePayment is transaction payer sent to invoice address
+type InvoicePayment struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	InvoiceID       uuid.UUID `db:"invoice_id" json:"invoice_id"`
+	TransactionHash string    `db:"transaction_hash" json:"transaction_hash"`
+	Sender          string    `db:"sender" json:"sender"`
+	// Amount is kept for compatibility, AmountUnits is exact amount in base units of token,
+	// it is not set for payments recorded before exact amounts
+	Amount      float64   `db:"amount" json:"amount"`
+	AmountUnits *Units    `db:"amount_units" json:"amount_units"`
+	Chain       string    `db:"chain" json:"chain"`
+	Token       string    `db:"token" json:"token"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+func (p *InvoicePayment) TableName() string {
+	return "invoice_payments"
+}
+
+func (p *InvoicePayment) ToInsertMap() map[string]interface{} {
+	return map[string]interface{}{
+		"invoice_id":       p.InvoiceID,
+		"transaction_hash": p.TransactionHash,
+		"sender":           p.Sender,
+		"amount":           p.Amount,
+		"amount_units":     p.AmountUnits,
+		"chain":            p.Chain,
+		"token":            p.Token,
+	}
+}
+
+func (p *InvoicePayment) Proto() *desc.InvoicePayment {
+	if p == nil {
+		return nil
+	}
+
+	return &desc.InvoicePayment{
+		TransactionHash: p.TransactionHash,
+		Sender:          p.Sender,
+		Amount:          p.Amount,
+		Chain:           p.Chain,
+		Token:           p.Token,
+		CreatedAt:       timestamppb.New(p.CreatedAt),
+		AmountUnits:     p.AmountUnits.String(),
+	}
+}
+
+func InvoicePaymentsToProto(payments []*InvoicePayment) []*desc.InvoicePayment {
+	if payments == nil {
+		return []*desc.InvoicePayment{}
+	}
+
+	result := make([]*desc.InvoicePayment, len(payments))
+	for i := 0; i < len(payments); i++ {
+		result[i] = payments[i].Proto()
+	}
+
+	return result
+}
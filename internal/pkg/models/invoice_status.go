@@ -0,0 +1,85 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/samber/lo"
+)
+
+// statusTransitions lists statuses invoice can move to from each status,
+// status without transitions to other statuses is final
+var statusTransitions = map[desc.InvoiceStatus][]desc.InvoiceStatus{
+	desc.InvoiceStatus_NEW: {
+		desc.InvoiceStatus_PENDING,
+		desc.InvoiceStatus_CANCELLED,
+		desc.InvoiceStatus_EXPIRED,
+	},
+	// payment method can be changed and quote refreshed until funds arrive
+	desc.InvoiceStatus_PENDING: {
+		desc.InvoiceStatus_PENDING,
+		desc.InvoiceStatus_PARTIALLY_PAID,
+		desc.InvoiceStatus_SENDING_TO_CLIENT,
+		desc.InvoiceStatus_CANCELLED,
+		desc.InvoiceStatus_EXPIRED,
+	},
+	desc.InvoiceStatus_PARTIALLY_PAID: {
+		desc.InvoiceStatus_PARTIALLY_PAID,
+		desc.InvoiceStatus_SENDING_TO_CLIENT,
+		desc.InvoiceStatus_CANCELLED,
+		desc.InvoiceStatus_EXPIRED,
+	},
+	desc.InvoiceStatus_SENDING_TO_CLIENT: {
+		desc.InvoiceStatus_SUCCESS,
+		desc.InvoiceStatus_OVERPAID,
+		desc.InvoiceStatus_MANUAL_CONTROL,
+	},
+	// payout which failed automatically is resolved by operator
+	desc.InvoiceStatus_MANUAL_CONTROL: {
+		desc.InvoiceStatus_SENDING_TO_CLIENT,
+		desc.InvoiceStatus_SUCCESS,
+		desc.InvoiceStatus_OVERPAID,
+		desc.InvoiceStatus_FAILED,
+	},
+	// funds received after invoice is closed are recorded to be refunded
+	desc.InvoiceStatus_CANCELLED: {
+		desc.InvoiceStatus_CANCELLED,
+	},
+	desc.InvoiceStatus_EXPIRED: {
+		desc.InvoiceStatus_EXPIRED,
+	},
+}
+
+var ErrInvalidStatusTransition = errors.New("invalid invoice status transition")
+
+// StatusTransitionError is returned when invoice update violates statusTransitions
+type StatusTransitionError struct {
+	From desc.InvoiceStatus
+	To   desc.InvoiceStatus
+}
+
+func (e *StatusTransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s", ErrInvalidStatusTransition, e.From.String(), e.To.String())
+}
+
+func (e *StatusTransitionError) Unwrap() error {
+	return ErrInvalidStatusTransition
+}
+
+// CanTransition reports whether invoice can move from status to status
+func CanTransition(from, to desc.InvoiceStatus) bool {
+	return lo.Contains(statusTransitions[from], to)
+}
+
+// PreviousStatuses returns statuses invoice can move to status from
+func PreviousStatuses(to desc.InvoiceStatus) []desc.InvoiceStatus {
+	previous := make([]desc.InvoiceStatus, 0)
+	for from, next := range statusTransitions {
+		if lo.Contains(next, to) {
+			previous = append(previous, from)
+		}
+	}
+
+	return previous
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Actor is the part of service which changed invoice status
+type Actor string
+
+const (
+	ActorAPI            Actor = "api"
+	ActorConsumer       Actor = "consumer"
+	ActorExpiryWorker   Actor = "expiry_worker"
+	ActorTransferWorker Actor = "transfer_worker"
+	ActorAdmin          Actor = "admin"
+)
+
+type actorKey struct{}
+
+// WithActor marks invoice updates made with ctx as made by actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns actor set by WithActor, updates of API handlers are not marked
+func ActorFromContext(ctx context.Context) Actor {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	if !ok {
+		return ActorAPI
+	}
+
+	return actor
+}
+
+type InvoiceStatusChange struct {
+	ID         int64              `db:"id" json:"id"`
+	InvoiceID  uuid.UUID          `db:"invoice_id" json:"invoice_id"`
+	FromStatus desc.InvoiceStatus `db:"from_status" json:"from_status"`
+	ToStatus   desc.InvoiceStatus `db:"to_status" json:"to_status"`
+	Actor      Actor              `db:"actor" json:"actor"`
+	Reason     string             `db:"reason" json:"reason"`
+	CreatedAt  time.Time          `db:"created_at" json:"created_at"`
+}
+
+func (c *InvoiceStatusChange) TableName() string {
+	return "invoice_status_history"
+}
+
+func (c *InvoiceStatusChange) ToInsertMap() map[string]interface{} {
+	return map[string]interface{}{
+		"invoice_id":  c.InvoiceID,
+		"from_status": c.FromStatus,
+		"to_status":   c.ToStatus,
+		"actor":       c.Actor,
+		"reason":      c.Reason,
+	}
+}
+
+func (c *InvoiceStatusChange) Proto() *desc.InvoiceStatusChange {
+	if c == nil {
+		return nil
+	}
+
+	return &desc.InvoiceStatusChange{
+		FromStatus: c.FromStatus,
+		ToStatus:   c.ToStatus,
+		Actor:      string(c.Actor),
+		Reason:     c.Reason,
+		CreatedAt:  timestamppb.New(c.CreatedAt),
+	}
+}
+
+func InvoiceStatusChangesToProto(changes []*InvoiceStatusChange) []*desc.InvoiceStatusChange {
+	if changes == nil {
+		return []*desc.InvoiceStatusChange{}
+	}
+
+	result := make([]*desc.InvoiceStatusChange, len(changes))
+	for i := 0; i < len(changes); i++ {
+		result[i] = changes[i].Proto()
+	}
+
+	return result
+}
@@ -0,0 +1,42 @@
+package models
+
+import desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+
+// PaymentMethod is chain and token pair invoices can be paid with,
+// amounts are exact decimal strings in token
+type PaymentMethod struct {
+	Chain     string
+	Token     string
+	Decimals  int32
+	Contract  string
+	MinAmount string
+	MaxAmount string
+}
+
+func (m *PaymentMethod) Proto() *desc.PaymentMethod {
+	if m == nil {
+		return nil
+	}
+
+	return &desc.PaymentMethod{
+		Chain:     m.Chain,
+		Token:     m.Token,
+		Decimals:  m.Decimals,
+		Contract:  m.Contract,
+		MinAmount: m.MinAmount,
+		MaxAmount: m.MaxAmount,
+	}
+}
+
+func PaymentMethodsToProto(methods []*PaymentMethod) []*desc.PaymentMethod {
+	if methods == nil {
+		return []*desc.PaymentMethod{}
+	}
+
+	result := make([]*desc.PaymentMethod, len(methods))
+	for i := 0; i < len(methods); i++ {
+		result[i] = methods[i].Proto()
+	}
+
+	return result
+}
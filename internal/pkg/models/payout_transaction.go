@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// PayoutTransaction is transaction forwarding invoice funds to client
+type PayoutTransaction struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	InvoiceID       uuid.UUID `db:"invoice_id" json:"invoice_id"`
+	TransactionHash string    `db:"transaction_hash" json:"transaction_hash"`
+	GasLimit        *uint64   `db:"gas_limit" json:"gas_limit"`
+	Attempt         int       `db:"attempt" json:"attempt"`
+	GasPrice        *uint64   `db:"gas_price" json:"gas_price"`
+	// EstimatedFee is fee suggested by crypto service at GasPrice, crypto service
+	// does not report fee actually paid
+	EstimatedFee *uint64   `db:"estimated_fee" json:"estimated_fee"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+func (t *PayoutTransaction) TableName() string {
+	return "payout_transactions"
+}
+
+func (t *PayoutTransaction) ToInsertMap() map[string]interface{} {
+	return map[string]interface{}{
+		"invoice_id":       t.InvoiceID,
+		"transaction_hash": t.TransactionHash,
+		"gas_limit":        t.GasLimit,
+		"attempt":          t.Attempt,
+		"gas_price":        t.GasPrice,
+		"estimated_fee":    t.EstimatedFee,
+	}
+}
+
+func (t *PayoutTransaction) Proto() *desc.PayoutTransaction {
+	if t == nil {
+		return nil
+	}
+
+	transaction := &desc.PayoutTransaction{
+		TransactionHash: t.TransactionHash,
+		Attempt:         uint32(t.Attempt),
+		CreatedAt:       timestamppb.New(t.CreatedAt),
+	}
+
+	if t.GasLimit != nil {
+		transaction.GasLimit = *t.GasLimit
+	}
+
+	if t.GasPrice != nil {
+		transaction.GasPrice = *t.GasPrice
+	}
+
+	if t.EstimatedFee != nil {
+		transaction.EstimatedFee = *t.EstimatedFee
+	}
+
+	return transaction
+}
+
+func PayoutTransactionsToProto(transactions []*PayoutTransaction) []*desc.PayoutTransaction {
+	if transactions == nil {
+		return []*desc.PayoutTransaction{}
+	}
+
+	result := make([]*desc.PayoutTransaction, len(transactions))
+	for i := 0; i < len(transactions); i++ {
+		result[i] = transactions[i].Proto()
+	}
+
+	return result
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrRefundAmountExceeded is returned when refunds of invoice exceed amount it received
+var ErrRefundAmountExceeded = errors.New("refund amount exceeds received amount")
+
+// RefundedStatuses lists statuses of refunds which are sent or are going to be sent to payer
+var RefundedStatuses = []desc.RefundStatus{
+	desc.RefundStatus_REFUND_PENDING,
+	desc.RefundStatus_REFUND_SENDING,
+	desc.RefundStatus_REFUND_SUCCESS,
+}
+
+type Refund struct {
+	ID              uuid.UUID         `db:"id" json:"id"`
+	InvoiceID       uuid.UUID         `db:"invoice_id" json:"invoice_id"`
+	Amount          float64           `db:"amount" json:"amount"`
+	Address         string            `db:"address" json:"address"`
+	Status          desc.RefundStatus `db:"status" json:"status"`
+	TransactionHash *string           `db:"transaction_hash" json:"transaction_hash"`
+	Attempts        int               `db:"attempts" json:"attempts"`
+	CreatedAt       time.Time         `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time         `db:"updated_at" json:"updated_at"`
+	// AmountUnits is exact amount in base units of token, Amount is kept for compatibility
+	AmountUnits *Units `db:"amount_units" json:"amount_units"`
+}
+
+func (r *Refund) TableName() string {
+	return "refunds"
+}
+
+func (r *Refund) ToInsertMap() map[string]interface{} {
+	return map[string]interface{}{
+		"invoice_id":   r.InvoiceID,
+		"amount":       r.Amount,
+		"amount_units": r.AmountUnits,
+		"address":      r.Address,
+		"status":       r.Status,
+	}
+}
+
+func (r *Refund) ToUpdateMap() map[string]interface{} {
+	updateData := map[string]interface{}{
+		"attempts":   r.Attempts,
+		"updated_at": time.Now(),
+	}
+
+	if r.Status != desc.RefundStatus_UNKNOWN_REFUND_STATUS {
+		updateData["status"] = r.Status
+	}
+
+	if r.TransactionHash != nil {
+		updateData["transaction_hash"] = r.TransactionHash
+	}
+
+	return updateData
+}
+
+func (r *Refund) Proto() *desc.Refund {
+	if r == nil {
+		return nil
+	}
+
+	refund := &desc.Refund{
+		Id:        r.ID.String(),
+		InvoiceId: r.InvoiceID.String(),
+		Amount:    r.Amount,
+		Address:   r.Address,
+		Status:    r.Status,
+		Attempts:  uint32(r.Attempts),
+		CreatedAt: timestamppb.New(r.CreatedAt),
+		UpdatedAt: timestamppb.New(r.UpdatedAt),
+
+		AmountUnits: r.AmountUnits.String(),
+	}
+
+	if r.TransactionHash != nil {
+		refund.TransactionHash = *r.TransactionHash
+	}
+
+	return refund
+}
+
+func RefundsToProto(refunds []*Refund) []*desc.Refund {
+	if refunds == nil {
+		return []*desc.Refund{}
+	}
+
+	result := make([]*desc.Refund, len(refunds))
+	for i := 0; i < len(refunds); i++ {
+		result[i] = refunds[i].Proto()
+	}
+
+	return result
+}
@@ -5,6 +5,8 @@ type Transaction struct {
 	Sender   string  `json:"sender"`
 	Receiver string  `json:"receiver"`
 	Amount   float64 `json:"amount"`
-	Chain    string  `json:"chain"`
-	Token    string  `json:"token"`
+	// AmountUnits is exact amount in base units of token, Amount is used if it is not sent
+	AmountUnits *Units `json:"amount_units"`
+	Chain       string `json:"chain"`
+	Token       string `json:"token"`
 }
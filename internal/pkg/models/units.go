@@ -0,0 +1,93 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Units is exact amount of token in its base units, e.g. wei. It is stored
+// as NUMERIC and encoded as JSON string, so that no precision is lost
+type Units big.Int
+
+func NewUnits(value *big.Int) *Units {
+	return (*Units)(new(big.Int).Set(value))
+}
+
+// ParseUnits parses decimal integer, e.g. "1500000000000000000"
+func ParseUnits(value string) (*Units, error) {
+	i, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid units %q", value)
+	}
+
+	return (*Units)(i), nil
+}
+
+func (u *Units) Int() *big.Int {
+	return (*big.Int)(u)
+}
+
+func (u *Units) String() string {
+	if u == nil {
+		return ""
+	}
+
+	return u.Int().String()
+}
+
+func (u *Units) Cmp(other *Units) int {
+	return u.Int().Cmp(other.Int())
+}
+
+func (u *Units) ScanNumeric(v pgtype.Numeric) error {
+	if !v.Valid || v.NaN || v.InfinityModifier != pgtype.Finite {
+		return fmt.Errorf("cannot scan %v into units", v)
+	}
+
+	value := new(big.Int).Set(v.Int)
+	switch {
+	case v.Exp > 0:
+		value.Mul(value, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(v.Exp)), nil))
+	case v.Exp < 0:
+		divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-v.Exp)), nil)
+		var remainder big.Int
+		value.QuoRem(value, divisor, &remainder)
+		if remainder.Sign() != 0 {
+			return fmt.Errorf("cannot scan fractional numeric into units")
+		}
+	}
+
+	*u = Units(*value)
+
+	return nil
+}
+
+func (u *Units) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{
+		Int:   new(big.Int).Set(u.Int()),
+		Valid: true,
+	}, nil
+}
+
+func (u *Units) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+func (u *Units) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	parsed, err := ParseUnits(value)
+	if err != nil {
+		return err
+	}
+
+	*u = *parsed
+
+	return nil
+}
@@ -1,8 +1,12 @@
 package models
 
+import "math/big"
+
 type WalletMessage struct {
 	Address string `json:"address"`
-	Balance int64  `json:"balance"`
-	Chain   string `json:"chain"`
-	Token   string `json:"token"`
+	// Balance is in base units of token, it is decoded as big integer
+	// because balances in wei overflow int64
+	Balance *big.Int `json:"balance"`
+	Chain   string   `json:"chain"`
+	Token   string   `json:"token"`
 }
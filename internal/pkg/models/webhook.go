@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Webhook struct {
+	ClientID  uuid.UUID `db:"client_id" json:"client_id"`
+	URL       string    `db:"url" json:"url"`
+	Secret    string    `db:"secret" json:"-"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (w *Webhook) TableName() string {
+	return "webhooks"
+}
+
+func (w *Webhook) ToInsertMap() map[string]interface{} {
+	return map[string]interface{}{
+		"client_id": w.ClientID,
+		"url":       w.URL,
+		"secret":    w.Secret,
+	}
+}
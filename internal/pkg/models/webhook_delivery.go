@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type WebhookDelivery struct {
+	ID            uuid.UUID                  `db:"id" json:"id"`
+	ClientID      uuid.UUID                  `db:"client_id" json:"client_id"`
+	InvoiceID     uuid.UUID                  `db:"invoice_id" json:"invoice_id"`
+	InvoiceStatus desc.InvoiceStatus         `db:"invoice_status" json:"invoice_status"`
+	URL           string                     `db:"url" json:"url"`
+	Payload       string                     `db:"payload" json:"payload"`
+	Status        desc.WebhookDeliveryStatus `db:"status" json:"status"`
+	Attempts      int                        `db:"attempts" json:"attempts"`
+	ResponseCode  *int                       `db:"response_code" json:"response_code"`
+	LastError     *string                    `db:"last_error" json:"last_error"`
+	NextRetryAt   time.Time                  `db:"next_retry_at" json:"next_retry_at"`
+	CreatedAt     time.Time                  `db:"created_at" json:"created_at"`
+	DeliveredAt   *time.Time                 `db:"delivered_at" json:"delivered_at"`
+}
+
+func (d *WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func (d *WebhookDelivery) ToUpdateMap() map[string]interface{} {
+	return map[string]interface{}{
+		"status":        d.Status,
+		"attempts":      d.Attempts,
+		"response_code": d.ResponseCode,
+		"last_error":    d.LastError,
+		"next_retry_at": d.NextRetryAt,
+		"delivered_at":  d.DeliveredAt,
+	}
+}
+
+func (d *WebhookDelivery) Proto() *desc.WebhookDelivery {
+	if d == nil {
+		return nil
+	}
+
+	delivery := &desc.WebhookDelivery{
+		Id:            d.ID.String(),
+		ClientId:      d.ClientID.String(),
+		InvoiceId:     d.InvoiceID.String(),
+		InvoiceStatus: d.InvoiceStatus,
+		Url:           d.URL,
+		Status:        d.Status,
+		Attempts:      uint32(d.Attempts),
+		NextRetryAt:   timestamppb.New(d.NextRetryAt),
+		CreatedAt:     timestamppb.New(d.CreatedAt),
+	}
+
+	if d.ResponseCode != nil {
+		delivery.ResponseCode = uint32(*d.ResponseCode)
+	}
+
+	if d.LastError != nil {
+		delivery.LastError = *d.LastError
+	}
+
+	if d.DeliveredAt != nil {
+		delivery.DeliveredAt = timestamppb.New(*d.DeliveredAt)
+	}
+
+	return delivery
+}
+
+func WebhookDeliveriesToProto(deliveries []*WebhookDelivery) []*desc.WebhookDelivery {
+	if deliveries == nil {
+		return []*desc.WebhookDelivery{}
+	}
+
+	result := make([]*desc.WebhookDelivery, len(deliveries))
+	for i := 0; i < len(deliveries); i++ {
+		result[i] = deliveries[i].Proto()
+	}
+
+	return result
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	"github.com/fidesy/sdk/common/postgres"
+	"github.com/google/uuid"
+)
+
+type ListAuditLogFilter struct {
+	InvoiceIDIn []uuid.UUID
+	OperatorIn  []string
+}
+
+func (s *Storage) ListAuditLog(ctx context.Context, filter ListAuditLogFilter, pagination postgres.Pagination) ([]*models.AuditLogEntry, error) {
+	query := postgres.Builder().
+		Select(auditLogEntryFields).
+		From(auditLogTable)
+
+	if len(filter.InvoiceIDIn) > 0 {
+		query = query.Where(sq.Eq{
+			"invoice_id": filter.InvoiceIDIn,
+		})
+	}
+
+	if len(filter.OperatorIn) > 0 {
+		query = query.Where(sq.Eq{
+			"operator": filter.OperatorIn,
+		})
+	}
+
+	query = query.OrderBy("id DESC")
+
+	query = query.
+		Limit(pagination.Limit()).
+		Offset(pagination.Offset())
+
+	return postgres.Select[models.AuditLogEntry](ctx, s.pool, query)
+}
+
+func (s *Storage) CreateAuditLogEntry(ctx context.Context, entry *models.AuditLogEntry) (*models.AuditLogEntry, error) {
+	query := postgres.Builder().
+		Insert(auditLogTable).
+		SetMap(entry.ToInsertMap()).
+		Suffix(fmt.Sprintf("RETURNING %s", auditLogEntryFields))
+
+	return postgres.Exec[models.AuditLogEntry](ctx, s.pool, query)
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,14 +11,50 @@ import (
 	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
 	"github.com/fidesy/sdk/common/postgres"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/samber/lo"
 )
 
 type ListInvoicesFilter struct {
-	IDIn        []uuid.UUID
-	AddressIn   []string
-	ClientIDIn  []uuid.UUID
-	StatusIn    []desc.InvoiceStatus
-	CreatedAtLt *time.Time
+	IDIn                []uuid.UUID
+	AddressIn           []string
+	ClientIDIn          []uuid.UUID
+	StatusIn            []desc.InvoiceStatus
+	CreatedAtGte        *time.Time
+	CreatedAtLt         *time.Time
+	IdempotencyKeyIn    []string
+	ExternalReferenceIn []string
+	ExpiresAtLt         *time.Time
+	ChainIn             []string
+	TokenIn             []string
+	PayerClientIDIn     []uuid.UUID
+	UsdCentsAmountGte   *int64
+	UsdCentsAmountLte   *int64
+	// Sort is created_at DESC by default
+	Sort InvoiceSort
+	// After returns invoices following cursor in Sort order
+	After *InvoiceCursor
+}
+
+type InvoiceSortField string
+
+const (
+	InvoiceSortFieldCreatedAt      InvoiceSortField = "created_at"
+	InvoiceSortFieldUsdCentsAmount InvoiceSortField = "usd_cents_amount"
+)
+
+type InvoiceSort struct {
+	Field InvoiceSortField `json:"field"`
+	Asc   bool             `json:"asc"`
+}
+
+// InvoiceCursor is position of invoice in list for keyset pagination,
+// ties of sort field are broken by id
+type InvoiceCursor struct {
+	Sort           InvoiceSort `json:"sort"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UsdCentsAmount int64       `json:"usd_cents_amount"`
+	ID             uuid.UUID   `json:"id"`
 }
 
 func (s *Storage) ListInvoices(ctx context.Context, filter ListInvoicesFilter, pagination postgres.Pagination) ([]*models.Invoice, error) {
@@ -25,6 +62,184 @@ func (s *Storage) ListInvoices(ctx context.Context, filter ListInvoicesFilter, p
 		Select(invoiceFields).
 		From(invoicesTable)
 
+	query = filterInvoices(query, filter)
+
+	sortField, direction, comparison := InvoiceSortFieldCreatedAt, "DESC", "<"
+	if filter.Sort.Field != "" {
+		sortField = filter.Sort.Field
+	}
+	if filter.Sort.Asc {
+		direction, comparison = "ASC", ">"
+	}
+
+	if filter.After != nil {
+		var sortValue interface{} = filter.After.CreatedAt
+		if sortField == InvoiceSortFieldUsdCentsAmount {
+			sortValue = filter.After.UsdCentsAmount
+		}
+
+		query = query.Where(sq.Expr(
+			fmt.Sprintf("(%s, id) %s (?, ?)", sortField, comparison),
+			sortValue, filter.After.ID,
+		))
+	}
+
+	query = query.OrderBy(
+		fmt.Sprintf("%s %s", sortField, direction),
+		fmt.Sprintf("id %s", direction),
+	)
+
+	query = query.
+		Limit(pagination.Limit()).
+		Offset(pagination.Offset())
+
+	return postgres.Select[models.Invoice](ctx, s.pool, query)
+}
+
+func (s *Storage) CountInvoices(ctx context.Context, filter ListInvoicesFilter) (uint64, error) {
+	query := postgres.Builder().
+		Select("COUNT(*) AS count").
+		From(invoicesTable)
+
+	query = filterInvoices(query, filter)
+
+	result, err := postgres.Exec[struct {
+		Count uint64 `db:"count"`
+	}](ctx, s.pool, query)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.Count, nil
+}
+
+// ClaimInvoices leases up to limit invoices matching filter to owner, oldest first.
+// Invoices leased by other owners and rows locked by concurrent claims are skipped
+func (s *Storage) ClaimInvoices(ctx context.Context, filter ListInvoicesFilter, owner string, lease time.Duration, limit uint64) ([]*models.Invoice, error) {
+	candidates := filterInvoices(sq.Select("id").From(invoicesTable), filter).
+		Where(leaseFree).
+		OrderBy("created_at").
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query := postgres.Builder().
+		Update(invoicesTable).
+		SetMap(leaseSetMap(owner, lease)).
+		Where(sq.Expr("id IN (?)", candidates)).
+		Suffix(fmt.Sprintf("RETURNING %s", invoiceFields))
+
+	return postgres.Select[models.Invoice](ctx, s.pool, query)
+}
+
+// ReleaseInvoice returns invoice leased by owner before lease expires
+func (s *Storage) ReleaseInvoice(ctx context.Context, invoiceID uuid.UUID, owner string) error {
+	query, args, err := postgres.Builder().
+		Update(invoicesTable).
+		SetMap(releaseSetMap()).
+		Where(sq.Eq{
+			"id":        invoiceID,
+			"locked_by": owner,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("query.ToSql: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// ExpireInvoices moves up to limit invoices which expired before now to EXPIRED
+// with single update and writes outbox event and status history of every invoice.
+// Rows locked by concurrent updates are skipped and expired on next call
+func (s *Storage) ExpireInvoices(ctx context.Context, now time.Time, limit uint64, reason string) ([]*models.Invoice, error) {
+	expiredStatuses := lo.Without(models.PreviousStatuses(desc.InvoiceStatus_EXPIRED), desc.InvoiceStatus_EXPIRED)
+
+	var invoices []*models.Invoice
+	err := postgres.WithTransaction(ctx, s.pool, func(tx pgx.Tx) error {
+		candidates, err := postgres.Select[struct {
+			ID     uuid.UUID          `db:"id"`
+			Status desc.InvoiceStatus `db:"status"`
+		}](
+			ctx,
+			tx,
+			postgres.Builder().
+				Select("id", "status").
+				From(invoicesTable).
+				Where(sq.Eq{
+					"status": expiredStatuses,
+				}).
+				Where(sq.Lt{
+					"expires_at": now,
+				}).
+				OrderBy("expires_at").
+				Limit(limit).
+				Suffix("FOR UPDATE SKIP LOCKED"),
+		)
+		if err != nil {
+			return fmt.Errorf("select candidates: %w", err)
+		}
+
+		if len(candidates) == 0 {
+			return nil
+		}
+
+		previousStatuses := make(map[uuid.UUID]desc.InvoiceStatus, len(candidates))
+		for _, candidate := range candidates {
+			previousStatuses[candidate.ID] = candidate.Status
+		}
+
+		invoices, err = postgres.Select[models.Invoice](
+			ctx,
+			tx,
+			postgres.Builder().
+				Update(invoicesTable).
+				SetMap(map[string]interface{}{
+					"status":  desc.InvoiceStatus_EXPIRED,
+					"version": sq.Expr("version + 1"),
+				}).
+				Where(sq.Eq{
+					"id":     lo.Keys(previousStatuses),
+					"status": expiredStatuses,
+				}).
+				Suffix(fmt.Sprintf("RETURNING %s", invoiceFields)),
+		)
+		if err != nil {
+			return fmt.Errorf("update invoices: %w", err)
+		}
+
+		for _, invoice := range invoices {
+			err = insertInvoiceStatusChange(ctx, tx, invoice, previousStatuses[invoice.ID], reason)
+			if err != nil {
+				return fmt.Errorf("insertInvoiceStatusChange: %w", err)
+			}
+
+			err = writeInvoiceOutbox(ctx, tx, invoice)
+			if err != nil {
+				return fmt.Errorf("writeInvoiceOutbox: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("WithTransaction: %w", err)
+	}
+
+	if s.notifier != nil {
+		for _, invoice := range invoices {
+			s.notifier.Notify(invoice)
+		}
+	}
+
+	return invoices, nil
+}
+
+func filterInvoices(query sq.SelectBuilder, filter ListInvoicesFilter) sq.SelectBuilder {
 	// does not show expired invoices
 	//query = query.Where(sq.NotEq{
 	//	"status": desc.InvoiceStatus_EXPIRED,
@@ -53,19 +268,67 @@ func (s *Storage) ListInvoices(ctx context.Context, filter ListInvoicesFilter, p
 		})
 	}
 
+	if len(filter.IdempotencyKeyIn) > 0 {
+		query = query.Where(sq.Eq{
+			"idempotency_key": filter.IdempotencyKeyIn,
+		})
+	}
+
+	if len(filter.ExternalReferenceIn) > 0 {
+		query = query.Where(sq.Eq{
+			"external_reference": filter.ExternalReferenceIn,
+		})
+	}
+
+	if len(filter.ChainIn) > 0 {
+		query = query.Where(sq.Eq{
+			"chain": filter.ChainIn,
+		})
+	}
+
+	if len(filter.TokenIn) > 0 {
+		query = query.Where(sq.Eq{
+			"token": filter.TokenIn,
+		})
+	}
+
+	if len(filter.PayerClientIDIn) > 0 {
+		query = query.Where(sq.Eq{
+			"payer_client_id": filter.PayerClientIDIn,
+		})
+	}
+
+	if filter.UsdCentsAmountGte != nil {
+		query = query.Where(sq.GtOrEq{
+			"usd_cents_amount": filter.UsdCentsAmountGte,
+		})
+	}
+
+	if filter.UsdCentsAmountLte != nil {
+		query = query.Where(sq.LtOrEq{
+			"usd_cents_amount": filter.UsdCentsAmountLte,
+		})
+	}
+
+	if filter.CreatedAtGte != nil {
+		query = query.Where(sq.GtOrEq{
+			"created_at": filter.CreatedAtGte,
+		})
+	}
+
 	if filter.CreatedAtLt != nil {
 		query = query.Where(sq.Lt{
 			"created_at": filter.CreatedAtLt,
 		})
 	}
 
-	query = query.OrderBy("created_at DESC")
-
-	query = query.
-		Limit(pagination.Limit()).
-		Offset(pagination.Offset())
+	if filter.ExpiresAtLt != nil {
+		query = query.Where(sq.Lt{
+			"expires_at": filter.ExpiresAtLt,
+		})
+	}
 
-	return postgres.Select[models.Invoice](ctx, s.pool, query)
+	return query
 }
 
 func (s *Storage) CreateInvoice(ctx context.Context, invoice *models.Invoice) (*models.Invoice, error) {
@@ -74,27 +337,123 @@ func (s *Storage) CreateInvoice(ctx context.Context, invoice *models.Invoice) (*
 		SetMap(invoice.ToInsertMap()).
 		Suffix(fmt.Sprintf("RETURNING %s", invoiceFields))
 
-	var invoiceModel models.Invoice
-	if err := postgres.ExecWithOutbox(ctx, s.pool, &invoiceModel, query); err != nil {
-		return nil, fmt.Errorf("postgres.ExecWithOutbox: %w", err)
+	var invoiceModel *models.Invoice
+	err := postgres.WithTransaction(ctx, s.pool, func(tx pgx.Tx) error {
+		var err error
+		invoiceModel, err = execInvoiceWithOutbox(ctx, tx, query)
+		if err != nil {
+			return fmt.Errorf("execInvoiceWithOutbox: %w", err)
+		}
+
+		return insertInvoiceStatusChange(ctx, tx, invoiceModel, desc.InvoiceStatus_UNKNOWN_STATUS, "")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("WithTransaction: %w", err)
 	}
 
-	return &invoiceModel, nil
+	return invoiceModel, nil
 }
 
-func (s *Storage) UpdateInvoice(ctx context.Context, invoice *models.Invoice) (*models.Invoice, error) {
+// InvoiceUpdate is written in the same transaction as invoice update
+type InvoiceUpdate struct {
+	// Reason is recorded in status history when update changes status
+	Reason string
+	// ProcessedMessageKey is recorded as processed, so that message is never applied again
+	ProcessedMessageKey string
+	// PayoutTransaction is recorded with the update, so that invoice is never
+	// completed without its payout and published with it
+	PayoutTransaction *models.PayoutTransaction
+	// RecordStatus records status history with Reason even if status is not changed
+	RecordStatus bool
+	// ClearGasOverrides unsets gas limit and gas price of invoice which are not set on invoice
+	ClearGasOverrides bool
+}
+
+func (s *Storage) UpdateInvoice(ctx context.Context, invoice *models.Invoice, update InvoiceUpdate) (*models.Invoice, error) {
+	updateMap := invoice.ToUpdateMap()
+	if update.ClearGasOverrides {
+		if _, ok := updateMap["gas_limit"]; !ok {
+			updateMap["gas_limit"] = nil
+		}
+		if _, ok := updateMap["gas_price"]; !ok {
+			updateMap["gas_price"] = nil
+		}
+	}
+
 	query := postgres.Builder().
 		Update(invoicesTable).
-		SetMap(invoice.ToUpdateMap()).
+		SetMap(updateMap).
 		Where(sq.Eq{
-			"id": invoice.ID,
+			"id":      invoice.ID,
+			"version": invoice.Version,
+			"status":  models.PreviousStatuses(invoice.Status),
 		}).
 		Suffix(fmt.Sprintf("RETURNING %s", invoiceFields))
 
-	var invoiceModel models.Invoice
-	if err := postgres.ExecWithOutbox(ctx, s.pool, &invoiceModel, query); err != nil {
-		return nil, fmt.Errorf("postgres.ExecWithOutbox: %w", err)
+	var invoiceModel *models.Invoice
+	err := postgres.WithTransaction(ctx, s.pool, func(tx pgx.Tx) error {
+		// previous status is read under row lock to record the transition
+		previous, err := postgres.Exec[models.Invoice](
+			ctx,
+			tx,
+			postgres.Builder().
+				Select(invoiceFields).
+				From(invoicesTable).
+				Where(sq.Eq{
+					"id": invoice.ID,
+				}).
+				Suffix("FOR UPDATE"),
+		)
+		if err != nil {
+			return fmt.Errorf("select previous: %w", err)
+		}
+
+		if update.PayoutTransaction != nil {
+			_, err = postgres.Exec[models.PayoutTransaction](ctx, tx, createPayoutTransactionQuery(update.PayoutTransaction))
+			if err != nil {
+				return fmt.Errorf("insert payout transaction: %w", err)
+			}
+		}
+
+		invoiceModel, err = execInvoiceWithOutbox(ctx, tx, query)
+		if err != nil {
+			// row exists, so it is not updated because of its version or status
+			if errors.Is(err, postgres.ErrNotFound) {
+				if previous.Version != invoice.Version {
+					return fmt.Errorf("%w: version = %d, expected = %d", models.ErrVersionConflict, previous.Version, invoice.Version)
+				}
+
+				return &models.StatusTransitionError{From: previous.Status, To: invoice.Status}
+			}
+
+			return fmt.Errorf("execInvoiceWithOutbox: %w", err)
+		}
+
+		if update.ProcessedMessageKey != "" {
+			messageSql, messageArgs, err := markMessageProcessedQuery(update.ProcessedMessageKey).ToSql()
+			if err != nil {
+				return fmt.Errorf("messageQuery.ToSql: %w", err)
+			}
+
+			_, err = tx.Exec(ctx, messageSql, messageArgs...)
+			if err != nil {
+				return fmt.Errorf("tx.Exec: %w", err)
+			}
+		}
+
+		if previous.Status == invoiceModel.Status && !update.RecordStatus {
+			return nil
+		}
+
+		return insertInvoiceStatusChange(ctx, tx, invoiceModel, previous.Status, update.Reason)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("WithTransaction: %w", err)
+	}
+
+	if s.notifier != nil {
+		s.notifier.Notify(invoiceModel)
 	}
 
-	return &invoiceModel, nil
+	return invoiceModel, nil
 }
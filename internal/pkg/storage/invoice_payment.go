@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	"github.com/fidesy/sdk/common/postgres"
+	"github.com/google/uuid"
+)
+
+func (s *Storage) ListInvoicePayments(ctx context.Context, invoiceIDs []uuid.UUID) ([]*models.InvoicePayment, error) {
+	query := postgres.Builder().
+		Select(invoicePaymentFields).
+		From(invoicePaymentsTable).
+		Where(sq.Eq{
+			"invoice_id": invoiceIDs,
+		}).
+		OrderBy("created_at ASC")
+
+	return postgres.Select[models.InvoicePayment](ctx, s.pool, query)
+}
+
+// CreateInvoicePayment returns postgres.ErrAlreadyExists if transaction is already recorded
+func (s *Storage) CreateInvoicePayment(ctx context.Context, payment *models.InvoicePayment) (*models.InvoicePayment, error) {
+	query := postgres.Builder().
+		Insert(invoicePaymentsTable).
+		SetMap(payment.ToInsertMap()).
+		Suffix(fmt.Sprintf("RETURNING %s", invoicePaymentFields))
+
+	return postgres.Exec[models.InvoicePayment](ctx, s.pool, query)
+}
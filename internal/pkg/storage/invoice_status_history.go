@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/fidesy/sdk/common/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *Storage) ListInvoiceStatusHistory(ctx context.Context, invoiceID uuid.UUID) ([]*models.InvoiceStatusChange, error) {
+	query := postgres.Builder().
+		Select(invoiceStatusChangeFields).
+		From(invoiceStatusHistoryTable).
+		Where(sq.Eq{
+			"invoice_id": invoiceID,
+		}).
+		OrderBy("id ASC")
+
+	return postgres.Select[models.InvoiceStatusChange](ctx, s.pool, query)
+}
+
+// insertInvoiceStatusChange records transition of invoice from status to its current status
+// and enqueues webhook delivery of the transition
+func insertInvoiceStatusChange(ctx context.Context, tx pgx.Tx, invoice *models.Invoice, from desc.InvoiceStatus, reason string) error {
+	change := &models.InvoiceStatusChange{
+		InvoiceID:  invoice.ID,
+		FromStatus: from,
+		ToStatus:   invoice.Status,
+		Actor:      models.ActorFromContext(ctx),
+		Reason:     reason,
+	}
+
+	query, args, err := postgres.Builder().
+		Insert(invoiceStatusHistoryTable).
+		SetMap(change.ToInsertMap()).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("query.ToSql: %w", err)
+	}
+
+	var changeID int64
+	err = tx.QueryRow(ctx, query, args...).Scan(&changeID)
+	if err != nil {
+		return fmt.Errorf("tx.QueryRow: %w", err)
+	}
+
+	err = enqueueInvoiceWebhook(ctx, tx, invoice, changeID)
+	if err != nil {
+		return fmt.Errorf("enqueueInvoiceWebhook: %w", err)
+	}
+
+	return nil
+}
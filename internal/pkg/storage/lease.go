@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Rows processed by background workers are leased to worker instance with
+// locked_by and locked_until columns, so that several replicas of the service
+// never process the same row at once. Lease of crashed instance expires
+// and the row is claimed by another one.
+
+// leaseFree matches rows which are not leased or which lease is expired
+var leaseFree = sq.Or{
+	sq.Eq{"locked_until": nil},
+	sq.Expr("locked_until < now()"),
+}
+
+func leaseSetMap(owner string, lease time.Duration) map[string]interface{} {
+	return map[string]interface{}{
+		"locked_by":    owner,
+		"locked_until": sq.Expr("now() + make_interval(secs => ?)", lease.Seconds()),
+	}
+}
+
+func releaseSetMap() map[string]interface{} {
+	return map[string]interface{}{
+		"locked_by":    nil,
+		"locked_until": nil,
+	}
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	"github.com/fidesy/sdk/common/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// enqueueWebhookQuery schedules delivery of invoice status change to client webhook if it is set,
+// every status change is delivered once
+const enqueueWebhookQuery = `
+INSERT INTO webhook_deliveries (client_id, invoice_id, invoice_status, status_change_id, url, payload)
+SELECT client_id, $2, $3, $4, url, $5
+FROM webhooks
+WHERE client_id = $1
+ON CONFLICT (status_change_id) DO NOTHING`
+
+// execInvoiceWithOutbox works as postgres.ExecWithOutbox within tx
+// and loads payout transactions of invoice published with the event
+func execInvoiceWithOutbox(ctx context.Context, tx pgx.Tx, query sq.Sqlizer) (*models.Invoice, error) {
+	invoice, err := postgres.Exec[models.Invoice](ctx, tx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.PayoutTransactions, err = postgres.Select[models.PayoutTransaction](ctx, tx, listPayoutTransactionsQuery([]uuid.UUID{invoice.ID}))
+	if err != nil {
+		return nil, fmt.Errorf("select payout transactions: %w", err)
+	}
+
+	err = writeInvoiceOutbox(ctx, tx, invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// writeInvoiceOutbox publishes invoice event to outbox
+func writeInvoiceOutbox(ctx context.Context, tx pgx.Tx, invoice *models.Invoice) error {
+	message, err := json.Marshal(invoice)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	outboxSql, outboxArgs, err := postgres.Builder().
+		Insert(invoicesOutboxTable).
+		SetMap(map[string]interface{}{
+			"message": message,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("outboxQuery.ToSql: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, outboxSql, outboxArgs...)
+	if err != nil {
+		return fmt.Errorf("tx.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// enqueueInvoiceWebhook enqueues webhook delivery of invoice status change
+func enqueueInvoiceWebhook(ctx context.Context, tx pgx.Tx, invoice *models.Invoice, statusChangeID int64) error {
+	payload, err := json.Marshal(invoice)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, enqueueWebhookQuery, invoice.ClientID, invoice.ID, invoice.Status, statusChangeID, string(payload))
+	if err != nil {
+		return fmt.Errorf("tx.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// execRefundWithOutbox works as postgres.ExecWithOutbox within tx
+func execRefundWithOutbox(ctx context.Context, tx pgx.Tx, query sq.Sqlizer) (*models.Refund, error) {
+	refund, err := postgres.Exec[models.Refund](ctx, tx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := json.Marshal(refund)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	outboxSql, outboxArgs, err := postgres.Builder().
+		Insert(refundsOutboxTable).
+		SetMap(map[string]interface{}{
+			"message": message,
+		}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("outboxQuery.ToSql: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, outboxSql, outboxArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("tx.Exec: %w", err)
+	}
+
+	return refund, nil
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	"github.com/fidesy/sdk/common/postgres"
+	"github.com/google/uuid"
+)
+
+func (s *Storage) ListPayoutTransactions(ctx context.Context, invoiceIDs []uuid.UUID) ([]*models.PayoutTransaction, error) {
+	return postgres.Select[models.PayoutTransaction](ctx, s.pool, listPayoutTransactionsQuery(invoiceIDs))
+}
+
+func (s *Storage) CreatePayoutTransaction(ctx context.Context, transaction *models.PayoutTransaction) (*models.PayoutTransaction, error) {
+	return postgres.Exec[models.PayoutTransaction](ctx, s.pool, createPayoutTransactionQuery(transaction))
+}
+
+func createPayoutTransactionQuery(transaction *models.PayoutTransaction) sq.InsertBuilder {
+	return postgres.Builder().
+		Insert(payoutTransactionsTable).
+		SetMap(transaction.ToInsertMap()).
+		Suffix(fmt.Sprintf("RETURNING %s", payoutTransactionFields))
+}
+
+func listPayoutTransactionsQuery(invoiceIDs []uuid.UUID) sq.SelectBuilder {
+	return postgres.Builder().
+		Select(payoutTransactionFields).
+		From(payoutTransactionsTable).
+		Where(sq.Eq{
+			"invoice_id": invoiceIDs,
+		}).
+		OrderBy("created_at ASC")
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/fidesy/sdk/common/postgres"
+)
+
+// IsMessageProcessed reports whether consumed message with key is already applied
+func (s *Storage) IsMessageProcessed(ctx context.Context, key string) (bool, error) {
+	_, err := postgres.Exec[struct {
+		Key string `db:"key"`
+	}](
+		ctx,
+		s.pool,
+		postgres.Builder().
+			Select("key").
+			From(processedMessagesTable).
+			Where(sq.Eq{
+				"key": key,
+			}),
+	)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// MarkMessageProcessed records consumed message with key, recording it again is no-op
+func (s *Storage) MarkMessageProcessed(ctx context.Context, key string) error {
+	query, args, err := markMessageProcessedQuery(key).ToSql()
+	if err != nil {
+		return fmt.Errorf("query.ToSql: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func markMessageProcessedQuery(key string) sq.InsertBuilder {
+	return postgres.Builder().
+		Insert(processedMessagesTable).
+		SetMap(map[string]interface{}{
+			"key": key,
+		}).
+		Suffix("ON CONFLICT (key) DO NOTHING")
+}
+
+// DeleteProcessedMessages deletes at most limit messages recorded before, returns number of deleted messages
+func (s *Storage) DeleteProcessedMessages(ctx context.Context, before time.Time, limit uint64) (int64, error) {
+	keysQuery := postgres.Builder().
+		Select("key").
+		From(processedMessagesTable).
+		Where(sq.Lt{
+			"created_at": before,
+		}).
+		Limit(limit)
+
+	query, args, err := postgres.Builder().
+		Delete(processedMessagesTable).
+		Where(sq.Expr("key IN (?)", keysQuery)).
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("query.ToSql: %w", err)
+	}
+
+	tag, err := s.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("pool.Exec: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
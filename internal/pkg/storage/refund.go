@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/fidesy/sdk/common/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type ListRefundsFilter struct {
+	IDIn        []uuid.UUID
+	InvoiceIDIn []uuid.UUID
+	StatusIn    []desc.RefundStatus
+}
+
+func (s *Storage) ListRefunds(ctx context.Context, filter ListRefundsFilter, pagination postgres.Pagination) ([]*models.Refund, error) {
+	query := postgres.Builder().
+		Select(refundFields).
+		From(refundsTable)
+
+	if len(filter.IDIn) > 0 {
+		query = query.Where(sq.Eq{
+			"id": filter.IDIn,
+		})
+	}
+
+	if len(filter.InvoiceIDIn) > 0 {
+		query = query.Where(sq.Eq{
+			"invoice_id": filter.InvoiceIDIn,
+		})
+	}
+
+	if len(filter.StatusIn) > 0 {
+		query = query.Where(sq.Eq{
+			"status": filter.StatusIn,
+		})
+	}
+
+	query = query.OrderBy("created_at DESC")
+
+	query = query.
+		Limit(pagination.Limit()).
+		Offset(pagination.Offset())
+
+	return postgres.Select[models.Refund](ctx, s.pool, query)
+}
+
+// ClaimRefunds leases up to limit refunds in statuses to owner, oldest first
+func (s *Storage) ClaimRefunds(ctx context.Context, statuses []desc.RefundStatus, owner string, lease time.Duration, limit uint64) ([]*models.Refund, error) {
+	candidates := sq.Select("id").
+		From(refundsTable).
+		Where(sq.Eq{
+			"status": statuses,
+		}).
+		Where(leaseFree).
+		OrderBy("created_at").
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query := postgres.Builder().
+		Update(refundsTable).
+		SetMap(leaseSetMap(owner, lease)).
+		Where(sq.Expr("id IN (?)", candidates)).
+		Suffix(fmt.Sprintf("RETURNING %s", refundFields))
+
+	return postgres.Select[models.Refund](ctx, s.pool, query)
+}
+
+// ReleaseRefund returns refund leased by owner before lease expires
+func (s *Storage) ReleaseRefund(ctx context.Context, refundID uuid.UUID, owner string) error {
+	query, args, err := postgres.Builder().
+		Update(refundsTable).
+		SetMap(releaseSetMap()).
+		Where(sq.Eq{
+			"id":        refundID,
+			"locked_by": owner,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("query.ToSql: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// RefundLimit is checked against refunds of invoice when refund is created
+type RefundLimit struct {
+	// InvoiceVersion is version of invoice Received is read from
+	InvoiceVersion int64
+	// Received is amount received by invoice in base units of token, refunds never exceed it
+	Received *big.Int
+	// Decimals of invoice token convert refunds created before exact amounts to base units
+	Decimals int
+}
+
+// CreateRefund creates refund if total amount of refunds of invoice stays within limit. Invoice
+// is locked while refunds are summed, so that concurrent refunds are checked one by one
+func (s *Storage) CreateRefund(ctx context.Context, refund *models.Refund, limit RefundLimit) (*models.Refund, error) {
+	var refundModel *models.Refund
+	err := postgres.WithTransaction(ctx, s.pool, func(tx pgx.Tx) error {
+		invoice, err := postgres.Exec[models.Invoice](
+			ctx,
+			tx,
+			postgres.Builder().
+				Select(invoiceFields).
+				From(invoicesTable).
+				Where(sq.Eq{
+					"id": refund.InvoiceID,
+				}).
+				Suffix("FOR UPDATE"),
+		)
+		if err != nil {
+			return fmt.Errorf("select invoice: %w", err)
+		}
+
+		if invoice.Version != limit.InvoiceVersion {
+			return fmt.Errorf("%w: version = %d, expected = %d", models.ErrVersionConflict, invoice.Version, limit.InvoiceVersion)
+		}
+
+		result, err := postgres.Exec[struct {
+			Refunded models.Units `db:"refunded"`
+		}](
+			ctx,
+			tx,
+			postgres.Builder().
+				Select().
+				Column(sq.Expr(
+					// refunds created before exact amounts have only float amount
+					"COALESCE(SUM(COALESCE(amount_units, ROUND(amount * 10::NUMERIC ^ ?))), 0) AS refunded",
+					limit.Decimals,
+				)).
+				From(refundsTable).
+				Where(sq.Eq{
+					"invoice_id": refund.InvoiceID,
+					"status":     models.RefundedStatuses,
+				}),
+		)
+		if err != nil {
+			return fmt.Errorf("sum refunds: %w", err)
+		}
+
+		refunded := result.Refunded.Int()
+		if new(big.Int).Add(refunded, refund.AmountUnits.Int()).Cmp(limit.Received) > 0 {
+			return fmt.Errorf(
+				"%w: requested = %s, refunded = %s, received = %s",
+				models.ErrRefundAmountExceeded, refund.AmountUnits.String(), refunded.String(), limit.Received.String(),
+			)
+		}
+
+		refundModel, err = execRefundWithOutbox(
+			ctx,
+			tx,
+			postgres.Builder().
+				Insert(refundsTable).
+				SetMap(refund.ToInsertMap()).
+				Suffix(fmt.Sprintf("RETURNING %s", refundFields)),
+		)
+		if err != nil {
+			return fmt.Errorf("execRefundWithOutbox: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("WithTransaction: %w", err)
+	}
+
+	return refundModel, nil
+}
+
+func (s *Storage) UpdateRefund(ctx context.Context, refund *models.Refund) (*models.Refund, error) {
+	query := postgres.Builder().
+		Update(refundsTable).
+		SetMap(refund.ToUpdateMap()).
+		Where(sq.Eq{
+			"id": refund.ID,
+		}).
+		Suffix(fmt.Sprintf("RETURNING %s", refundFields))
+
+	var refundModel models.Refund
+	if err := postgres.ExecWithOutbox(ctx, s.pool, &refundModel, query); err != nil {
+		return nil, fmt.Errorf("postgres.ExecWithOutbox: %w", err)
+	}
+
+	return &refundModel, nil
+}
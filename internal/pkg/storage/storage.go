@@ -1,15 +1,25 @@
 package storage
 
 import (
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-type Storage struct {
-	pool *pgxpool.Pool
-}
+type (
+	Storage struct {
+		pool     *pgxpool.Pool
+		notifier InvoiceNotifier
+	}
+
+	// InvoiceNotifier receives every invoice committed by UpdateInvoice
+	InvoiceNotifier interface {
+		Notify(invoice *models.Invoice)
+	}
+)
 
-func New(pool *pgxpool.Pool) *Storage {
+func New(pool *pgxpool.Pool, notifier InvoiceNotifier) *Storage {
 	return &Storage{
-		pool: pool,
+		pool:     pool,
+		notifier: notifier,
 	}
 }
@@ -9,8 +9,33 @@ import (
 )
 
 var (
-	invoicesTable = (&models.Invoice{}).TableName()
-	invoiceFields = modelColumns(&models.Invoice{})
+	invoicesTable       = (&models.Invoice{}).TableName()
+	invoiceFields       = modelColumns(&models.Invoice{})
+	invoicesOutboxTable = fmt.Sprintf("%s_outbox", invoicesTable)
+
+	invoiceStatusHistoryTable = (&models.InvoiceStatusChange{}).TableName()
+	invoiceStatusChangeFields = modelColumns(&models.InvoiceStatusChange{})
+
+	payoutTransactionsTable = (&models.PayoutTransaction{}).TableName()
+	payoutTransactionFields = modelColumns(&models.PayoutTransaction{})
+
+	invoicePaymentsTable = (&models.InvoicePayment{}).TableName()
+	invoicePaymentFields = modelColumns(&models.InvoicePayment{})
+
+	auditLogTable       = (&models.AuditLogEntry{}).TableName()
+	auditLogEntryFields = modelColumns(&models.AuditLogEntry{})
+
+	refundsTable       = (&models.Refund{}).TableName()
+	refundFields       = modelColumns(&models.Refund{})
+	refundsOutboxTable = fmt.Sprintf("%s_outbox", refundsTable)
+
+	webhooksTable = (&models.Webhook{}).TableName()
+	webhookFields = modelColumns(&models.Webhook{})
+
+	webhookDeliveriesTable = (&models.WebhookDelivery{}).TableName()
+	webhookDeliveryFields  = modelColumns(&models.WebhookDelivery{})
+
+	processedMessagesTable = "processed_messages"
 )
 
 type Model interface {
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	desc "github.com/fidesy-pay/invoices-service/pkg/invoices-service"
+	"github.com/fidesy/sdk/common/postgres"
+	"github.com/google/uuid"
+)
+
+type ListWebhookDeliveriesFilter struct {
+	IDIn           []uuid.UUID
+	ClientIDIn     []uuid.UUID
+	InvoiceIDIn    []uuid.UUID
+	StatusIn       []desc.WebhookDeliveryStatus
+	NextRetryAtLte *time.Time
+}
+
+func (s *Storage) SaveWebhook(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+	query := postgres.Builder().
+		Insert(webhooksTable).
+		SetMap(webhook.ToInsertMap()).
+		Suffix(fmt.Sprintf(
+			"ON CONFLICT (client_id) DO UPDATE SET url = EXCLUDED.url, secret = EXCLUDED.secret, updated_at = now() RETURNING %s",
+			webhookFields,
+		))
+
+	return postgres.Exec[models.Webhook](ctx, s.pool, query)
+}
+
+func (s *Storage) ListWebhooks(ctx context.Context, clientIDs []uuid.UUID) ([]*models.Webhook, error) {
+	query := postgres.Builder().
+		Select(webhookFields).
+		From(webhooksTable).
+		Where(sq.Eq{
+			"client_id": clientIDs,
+		})
+
+	return postgres.Select[models.Webhook](ctx, s.pool, query)
+}
+
+func (s *Storage) ListWebhookDeliveries(ctx context.Context, filter ListWebhookDeliveriesFilter, pagination postgres.Pagination) ([]*models.WebhookDelivery, error) {
+	query := filterWebhookDeliveries(
+		postgres.Builder().
+			Select(webhookDeliveryFields).
+			From(webhookDeliveriesTable),
+		filter,
+	)
+
+	query = query.OrderBy("created_at DESC")
+
+	query = query.
+		Limit(pagination.Limit()).
+		Offset(pagination.Offset())
+
+	return postgres.Select[models.WebhookDelivery](ctx, s.pool, query)
+}
+
+// ClaimWebhookDeliveries leases up to limit deliveries matching filter to owner, oldest first.
+// Deliveries leased by other owners and rows locked by concurrent claims are skipped
+func (s *Storage) ClaimWebhookDeliveries(ctx context.Context, filter ListWebhookDeliveriesFilter, owner string, lease time.Duration, limit uint64) ([]*models.WebhookDelivery, error) {
+	candidates := filterWebhookDeliveries(sq.Select("id").From(webhookDeliveriesTable), filter).
+		Where(leaseFree).
+		OrderBy("created_at").
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query := postgres.Builder().
+		Update(webhookDeliveriesTable).
+		SetMap(leaseSetMap(owner, lease)).
+		Where(sq.Expr("id IN (?)", candidates)).
+		Suffix(fmt.Sprintf("RETURNING %s", webhookDeliveryFields))
+
+	return postgres.Select[models.WebhookDelivery](ctx, s.pool, query)
+}
+
+// ReleaseWebhookDelivery returns delivery leased by owner before lease expires
+func (s *Storage) ReleaseWebhookDelivery(ctx context.Context, deliveryID uuid.UUID, owner string) error {
+	query, args, err := postgres.Builder().
+		Update(webhookDeliveriesTable).
+		SetMap(releaseSetMap()).
+		Where(sq.Eq{
+			"id":        deliveryID,
+			"locked_by": owner,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("query.ToSql: %w", err)
+	}
+
+	_, err = s.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func filterWebhookDeliveries(query sq.SelectBuilder, filter ListWebhookDeliveriesFilter) sq.SelectBuilder {
+	if len(filter.IDIn) > 0 {
+		query = query.Where(sq.Eq{
+			"id": filter.IDIn,
+		})
+	}
+
+	if len(filter.ClientIDIn) > 0 {
+		query = query.Where(sq.Eq{
+			"client_id": filter.ClientIDIn,
+		})
+	}
+
+	if len(filter.InvoiceIDIn) > 0 {
+		query = query.Where(sq.Eq{
+			"invoice_id": filter.InvoiceIDIn,
+		})
+	}
+
+	if len(filter.StatusIn) > 0 {
+		query = query.Where(sq.Eq{
+			"status": filter.StatusIn,
+		})
+	}
+
+	if filter.NextRetryAtLte != nil {
+		query = query.Where(sq.LtOrEq{
+			"next_retry_at": filter.NextRetryAtLte,
+		})
+	}
+
+	return query
+}
+
+func (s *Storage) UpdateWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	query := postgres.Builder().
+		Update(webhookDeliveriesTable).
+		SetMap(delivery.ToUpdateMap()).
+		Where(sq.Eq{
+			"id": delivery.ID,
+		}).
+		Suffix(fmt.Sprintf("RETURNING %s", webhookDeliveryFields))
+
+	return postgres.Exec[models.WebhookDelivery](ctx, s.pool, query)
+}
@@ -0,0 +1,136 @@
+package tokens
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/common"
+)
+
+// Token is payment token on chain, token without contract is native token of chain
+type Token struct {
+	Chain    string
+	Symbol   string
+	Decimals int
+	Contract string
+	// MinAmount is minimal invoice amount in token, it covers payout fees
+	MinAmount float64
+	// MaxAmount is maximal invoice amount in token, 0 if amount is not limited
+	MaxAmount float64
+	Enabled   bool
+}
+
+// FromBaseUnits converts amount in base units of token, e.g. wei, to token amount.
+// Result is approximate and is used only where float amount is kept for compatibility
+func (t Token) FromBaseUnits(units *big.Int) float64 {
+	amount, _ := new(big.Rat).SetFrac(units, common.Pow10(t.Decimals)).Float64()
+	return amount
+}
+
+// Format returns exact token amount of units as decimal string
+func (t Token) Format(units *big.Int) string {
+	return new(big.Rat).SetFrac(units, common.Pow10(t.Decimals)).FloatString(t.Decimals)
+}
+
+// ToBaseUnits converts token amount to base units of token rounding to the nearest unit
+func (t Token) ToBaseUnits(amount float64) *big.Int {
+	return common.RoundRat(t.scale(common.FloatToRat(amount)))
+}
+
+// MinBaseUnits returns min amount of token in base units
+func (t Token) MinBaseUnits() *big.Int {
+	return t.ToBaseUnits(t.MinAmount)
+}
+
+// MaxBaseUnits returns max amount of token in base units, nil if amount is not limited
+func (t Token) MaxBaseUnits() *big.Int {
+	if t.MaxAmount <= 0 {
+		return nil
+	}
+
+	return t.ToBaseUnits(t.MaxAmount)
+}
+
+// Quote converts usd amount to base units of token at price, amount is rounded up
+// so that client is never underpaid
+func (t Token) Quote(usdAmount *big.Rat, priceUSD float64) *big.Int {
+	amount := new(big.Rat).Quo(usdAmount, common.FloatToRat(priceUSD))
+	return common.CeilRat(t.scale(amount))
+}
+
+func (t Token) scale(amount *big.Rat) *big.Rat {
+	return amount.Mul(amount, new(big.Rat).SetInt(common.Pow10(t.Decimals)))
+}
+
+// IsNative reports whether token is native token of chain, which pays for gas
+func (t Token) IsNative() bool {
+	return t.Contract == ""
+}
+
+type key struct {
+	chain  string
+	symbol string
+}
+
+// Registry holds tokens supported by invoices, tokens are looked up
+// by chain and symbol case-insensitively
+type Registry struct {
+	tokens map[key]Token
+}
+
+func NewRegistry(tokens []Token) *Registry {
+	registry := &Registry{
+		tokens: make(map[key]Token, len(tokens)),
+	}
+
+	for _, token := range tokens {
+		registry.tokens[newKey(token.Chain, token.Symbol)] = token
+	}
+
+	return registry
+}
+
+func newKey(chain, symbol string) key {
+	return key{
+		chain:  strings.ToLower(chain),
+		symbol: strings.ToUpper(symbol),
+	}
+}
+
+// Get returns token whether it is enabled or not
+func (r *Registry) Get(chain, symbol string) (Token, bool) {
+	token, ok := r.tokens[newKey(chain, symbol)]
+	return token, ok
+}
+
+// Native returns native token of chain
+func (r *Registry) Native(chain string) (Token, bool) {
+	for _, token := range r.tokens {
+		if token.IsNative() && strings.EqualFold(token.Chain, chain) {
+			return token, true
+		}
+	}
+
+	return Token{}, false
+}
+
+// ListEnabled returns enabled tokens sorted by chain and symbol
+func (r *Registry) ListEnabled() []Token {
+	tokens := make([]Token, 0, len(r.tokens))
+	for _, token := range r.tokens {
+		if token.Enabled {
+			tokens = append(tokens, token)
+		}
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].Chain != tokens[j].Chain {
+			return tokens[i].Chain < tokens[j].Chain
+		}
+
+		return tokens[i].Symbol < tokens[j].Symbol
+	})
+
+	return tokens
+}
@@ -0,0 +1,61 @@
+package watcher
+
+import (
+	"sync"
+
+	"github.com/fidesy-pay/invoices-service/internal/pkg/models"
+	"github.com/google/uuid"
+)
+
+// Hub fans out invoice updates of this process to local subscribers
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[uuid.UUID]map[chan *models.Invoice]struct{}
+}
+
+func New() *Hub {
+	return &Hub{
+		subscribers: make(map[uuid.UUID]map[chan *models.Invoice]struct{}),
+	}
+}
+
+// Subscribe returns channel with updates of invoice, call returned func to unsubscribe
+func (h *Hub) Subscribe(invoiceID uuid.UUID) (<-chan *models.Invoice, func()) {
+	// only the latest state matters, slow subscriber skips intermediate updates
+	ch := make(chan *models.Invoice, 1)
+
+	h.mu.Lock()
+	if _, ok := h.subscribers[invoiceID]; !ok {
+		h.subscribers[invoiceID] = make(map[chan *models.Invoice]struct{})
+	}
+	h.subscribers[invoiceID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		delete(h.subscribers[invoiceID], ch)
+		if len(h.subscribers[invoiceID]) == 0 {
+			delete(h.subscribers, invoiceID)
+		}
+	}
+}
+
+// Notify never blocks, pending update of subscriber is replaced with the new one
+func (h *Hub) Notify(invoice *models.Invoice) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers[invoice.ID] {
+		select {
+		case <-ch:
+		default:
+		}
+
+		select {
+		case ch <- invoice:
+		default:
+		}
+	}
+}
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE invoices ADD COLUMN cancellation_reason TEXT DEFAULT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE invoices DROP COLUMN cancellation_reason;
+-- +goose StatementEnd
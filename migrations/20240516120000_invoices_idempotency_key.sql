@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE invoices ADD COLUMN idempotency_key TEXT DEFAULT NULL;
+CREATE UNIQUE INDEX invoices_client_id_idempotency_key_idx ON invoices (client_id, idempotency_key)
+    WHERE idempotency_key IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX invoices_client_id_idempotency_key_idx;
+ALTER TABLE invoices DROP COLUMN idempotency_key;
+-- +goose StatementEnd
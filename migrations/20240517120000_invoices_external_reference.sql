@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE invoices ADD COLUMN external_reference TEXT DEFAULT NULL;
+ALTER TABLE invoices ADD COLUMN description TEXT DEFAULT NULL;
+ALTER TABLE invoices ADD COLUMN metadata JSONB DEFAULT NULL;
+CREATE INDEX invoices_client_id_external_reference_idx ON invoices (client_id, external_reference);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX invoices_client_id_external_reference_idx;
+ALTER TABLE invoices DROP COLUMN metadata;
+ALTER TABLE invoices DROP COLUMN description;
+ALTER TABLE invoices DROP COLUMN external_reference;
+-- +goose StatementEnd
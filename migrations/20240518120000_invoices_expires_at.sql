@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE invoices ADD COLUMN expires_at TIMESTAMP;
+-- payment window of existing invoices is expire-interval config, which is 20m in production
+UPDATE invoices SET expires_at = COALESCE(expires_at, created_at + INTERVAL '20 minutes');
+-- invoices created by instances which do not set expires_at yet get the same payment window
+ALTER TABLE invoices ALTER COLUMN expires_at SET DEFAULT now() + INTERVAL '20 minutes';
+ALTER TABLE invoices ALTER COLUMN expires_at SET NOT NULL;
+CREATE INDEX invoices_status_expires_at_idx ON invoices (status, expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX invoices_status_expires_at_idx;
+ALTER TABLE invoices DROP COLUMN expires_at;
+-- +goose StatementEnd
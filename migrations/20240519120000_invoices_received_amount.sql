@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE invoices ADD COLUMN received_amount NUMERIC(38, 18) DEFAULT NULL;
+ALTER TABLE invoices ADD COLUMN overpaid_amount NUMERIC(38, 18) DEFAULT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE invoices DROP COLUMN overpaid_amount;
+ALTER TABLE invoices DROP COLUMN received_amount;
+-- +goose StatementEnd
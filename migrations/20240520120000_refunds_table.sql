@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE refunds
+(
+    id               UUID      DEFAULT uuid_generate_v4() NOT NULL
+        PRIMARY KEY,
+    invoice_id       UUID                                 NOT NULL
+        REFERENCES invoices (id),
+    amount           NUMERIC(38, 18)                      NOT NULL,
+    address          TEXT                                 NOT NULL,
+    status           INT                                  NOT NULL,
+    transaction_hash TEXT,
+    attempts         INT       DEFAULT 0                  NOT NULL,
+    created_at       TIMESTAMP DEFAULT now()              NOT NULL,
+    updated_at       TIMESTAMP DEFAULT now()              NOT NULL
+);
+
+CREATE INDEX refunds_invoice_id_idx ON refunds (invoice_id);
+CREATE INDEX refunds_status_idx ON refunds (status);
+
+CREATE TABLE refunds_outbox (
+    id SERIAL PRIMARY KEY,
+    message TEXT
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE refunds_outbox;
+DROP TABLE refunds;
+-- +goose StatementEnd
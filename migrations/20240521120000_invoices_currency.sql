@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE invoices ADD COLUMN currency TEXT DEFAULT 'USD' NOT NULL;
+ALTER TABLE invoices ADD COLUMN amount BIGINT;
+UPDATE invoices SET amount = usd_cents_amount;
+ALTER TABLE invoices ALTER COLUMN amount SET NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE invoices DROP COLUMN amount;
+ALTER TABLE invoices DROP COLUMN currency;
+-- +goose StatementEnd
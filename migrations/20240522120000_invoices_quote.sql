@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE invoices ADD COLUMN price_usd NUMERIC(38, 18) DEFAULT NULL;
+ALTER TABLE invoices ADD COLUMN quote_expires_at TIMESTAMP DEFAULT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE invoices DROP COLUMN quote_expires_at;
+ALTER TABLE invoices DROP COLUMN price_usd;
+-- +goose StatementEnd
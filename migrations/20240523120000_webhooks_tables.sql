@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE webhooks
+(
+    client_id  UUID                    NOT NULL
+        PRIMARY KEY,
+    url        TEXT                    NOT NULL,
+    secret     TEXT                    NOT NULL,
+    created_at TIMESTAMP DEFAULT now() NOT NULL,
+    updated_at TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE TABLE webhook_deliveries
+(
+    id             UUID      DEFAULT uuid_generate_v4() NOT NULL
+        PRIMARY KEY,
+    client_id      UUID                                 NOT NULL,
+    invoice_id     UUID                                 NOT NULL
+        REFERENCES invoices (id),
+    invoice_status INT                                  NOT NULL,
+    url            TEXT                                 NOT NULL,
+    payload        TEXT                                 NOT NULL,
+    status         INT       DEFAULT 1                  NOT NULL,
+    attempts       INT       DEFAULT 0                  NOT NULL,
+    response_code  INT,
+    last_error     TEXT,
+    next_retry_at  TIMESTAMP DEFAULT now()              NOT NULL,
+    created_at     TIMESTAMP DEFAULT now()              NOT NULL,
+    delivered_at   TIMESTAMP
+);
+
+-- one delivery per invoice status change
+CREATE UNIQUE INDEX webhook_deliveries_invoice_id_invoice_status_idx ON webhook_deliveries (invoice_id, invoice_status);
+CREATE INDEX webhook_deliveries_status_next_retry_at_idx ON webhook_deliveries (status, next_retry_at);
+CREATE INDEX webhook_deliveries_client_id_idx ON webhook_deliveries (client_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE webhook_deliveries;
+DROP TABLE webhooks;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX invoices_usd_cents_amount_id_idx ON invoices (usd_cents_amount, id);
+CREATE INDEX invoices_payer_client_id_idx ON invoices (payer_client_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX invoices_payer_client_id_idx;
+DROP INDEX invoices_usd_cents_amount_id_idx;
+-- +goose StatementEnd
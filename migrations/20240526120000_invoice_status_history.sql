@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE invoice_status_history
+(
+    id          BIGSERIAL                  NOT NULL
+        PRIMARY KEY,
+    invoice_id  UUID                       NOT NULL
+        REFERENCES invoices (id),
+    from_status INT                        NOT NULL,
+    to_status   INT                        NOT NULL,
+    actor       TEXT                       NOT NULL,
+    reason      TEXT      DEFAULT ''       NOT NULL,
+    created_at  TIMESTAMP DEFAULT now()    NOT NULL
+);
+
+CREATE INDEX invoice_status_history_invoice_id_idx ON invoice_status_history (invoice_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE invoice_status_history;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE payout_transactions
+(
+    id               UUID      DEFAULT uuid_generate_v4() NOT NULL
+        PRIMARY KEY,
+    invoice_id       UUID                                 NOT NULL
+        REFERENCES invoices (id),
+    transaction_hash TEXT                                 NOT NULL,
+    gas_limit        BIGINT,
+    attempt          INT                                  NOT NULL,
+    created_at       TIMESTAMP DEFAULT now()              NOT NULL
+);
+
+CREATE INDEX payout_transactions_invoice_id_idx ON payout_transactions (invoice_id);
+CREATE UNIQUE INDEX payout_transactions_transaction_hash_idx ON payout_transactions (transaction_hash);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE payout_transactions;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE invoice_payments
+(
+    id               UUID      DEFAULT uuid_generate_v4() NOT NULL
+        PRIMARY KEY,
+    invoice_id       UUID                                 NOT NULL
+        REFERENCES invoices (id),
+    transaction_hash TEXT                                 NOT NULL,
+    sender           TEXT                                 NOT NULL,
+    amount           NUMERIC(38, 18)                      NOT NULL,
+    chain            TEXT                                 NOT NULL,
+    token            TEXT                                 NOT NULL,
+    created_at       TIMESTAMP DEFAULT now()              NOT NULL
+);
+
+CREATE INDEX invoice_payments_invoice_id_idx ON invoice_payments (invoice_id);
+-- transaction sending to several addresses pays several invoices
+CREATE UNIQUE INDEX invoice_payments_chain_transaction_hash_invoice_id_idx
+    ON invoice_payments (chain, transaction_hash, invoice_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE invoice_payments;
+-- +goose StatementEnd
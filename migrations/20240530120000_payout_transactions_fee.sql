@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE payout_transactions ADD COLUMN gas_price BIGINT DEFAULT NULL;
+ALTER TABLE payout_transactions ADD COLUMN estimated_fee BIGINT DEFAULT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE payout_transactions DROP COLUMN estimated_fee;
+ALTER TABLE payout_transactions DROP COLUMN gas_price;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE admin_audit_log
+(
+    id         BIGSERIAL               NOT NULL
+        PRIMARY KEY,
+    operator   TEXT                    NOT NULL,
+    action     TEXT                    NOT NULL,
+    invoice_id UUID,
+    note       TEXT      DEFAULT ''    NOT NULL,
+    request    JSONB                   NOT NULL,
+    error      TEXT,
+    created_at TIMESTAMP DEFAULT now() NOT NULL
+);
+
+CREATE INDEX admin_audit_log_invoice_id_idx ON admin_audit_log (invoice_id);
+CREATE INDEX admin_audit_log_operator_idx ON admin_audit_log (operator);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE admin_audit_log;
+-- +goose StatementEnd
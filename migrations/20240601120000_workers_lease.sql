@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE invoices ADD COLUMN locked_by TEXT DEFAULT NULL;
+ALTER TABLE invoices ADD COLUMN locked_until TIMESTAMP DEFAULT NULL;
+
+ALTER TABLE refunds ADD COLUMN locked_by TEXT DEFAULT NULL;
+ALTER TABLE refunds ADD COLUMN locked_until TIMESTAMP DEFAULT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE refunds DROP COLUMN locked_until;
+ALTER TABLE refunds DROP COLUMN locked_by;
+
+ALTER TABLE invoices DROP COLUMN locked_until;
+ALTER TABLE invoices DROP COLUMN locked_by;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE processed_messages
+(
+    key        TEXT                    NOT NULL
+        PRIMARY KEY,
+    created_at TIMESTAMP DEFAULT now() NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE processed_messages;
+-- +goose StatementEnd
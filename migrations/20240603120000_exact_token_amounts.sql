@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE invoices ADD COLUMN token_amount_units NUMERIC(78, 0) DEFAULT NULL;
+ALTER TABLE invoices ADD COLUMN received_amount_units NUMERIC(78, 0) DEFAULT NULL;
+ALTER TABLE invoices ADD COLUMN overpaid_amount_units NUMERIC(78, 0) DEFAULT NULL;
+ALTER TABLE invoices ADD COLUMN token_decimals INT DEFAULT NULL;
+
+ALTER TABLE invoice_payments ADD COLUMN amount_units NUMERIC(78, 0) DEFAULT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE invoice_payments DROP COLUMN amount_units;
+
+ALTER TABLE invoices DROP COLUMN token_decimals;
+ALTER TABLE invoices DROP COLUMN overpaid_amount_units;
+ALTER TABLE invoices DROP COLUMN received_amount_units;
+ALTER TABLE invoices DROP COLUMN token_amount_units;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE webhook_deliveries ADD COLUMN locked_by TEXT DEFAULT NULL;
+ALTER TABLE webhook_deliveries ADD COLUMN locked_until TIMESTAMP DEFAULT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE webhook_deliveries DROP COLUMN locked_until;
+ALTER TABLE webhook_deliveries DROP COLUMN locked_by;
+-- +goose StatementEnd
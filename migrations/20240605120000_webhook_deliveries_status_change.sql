@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE webhook_deliveries ADD COLUMN status_change_id BIGINT DEFAULT NULL
+    REFERENCES invoice_status_history (id);
+
+-- one delivery per invoice status change, invoice can reach the same status several times
+DROP INDEX webhook_deliveries_invoice_id_invoice_status_idx;
+CREATE INDEX webhook_deliveries_invoice_id_idx ON webhook_deliveries (invoice_id);
+CREATE UNIQUE INDEX webhook_deliveries_status_change_id_idx ON webhook_deliveries (status_change_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX webhook_deliveries_status_change_id_idx;
+DROP INDEX webhook_deliveries_invoice_id_idx;
+CREATE UNIQUE INDEX webhook_deliveries_invoice_id_invoice_status_idx ON webhook_deliveries (invoice_id, invoice_status);
+ALTER TABLE webhook_deliveries DROP COLUMN status_change_id;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE refunds ADD COLUMN amount_units NUMERIC(78, 0) DEFAULT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE refunds DROP COLUMN amount_units;
+-- +goose StatementEnd
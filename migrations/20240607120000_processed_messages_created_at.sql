@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX processed_messages_created_at_idx ON processed_messages (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX processed_messages_created_at_idx;
+-- +goose StatementEnd
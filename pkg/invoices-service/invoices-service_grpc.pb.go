@@ -19,10 +19,20 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	InvoicesService_CreateInvoice_FullMethodName = "/invoices_service.InvoicesService/CreateInvoice"
-	InvoicesService_CheckInvoice_FullMethodName  = "/invoices_service.InvoicesService/CheckInvoice"
-	InvoicesService_UpdateInvoice_FullMethodName = "/invoices_service.InvoicesService/UpdateInvoice"
-	InvoicesService_ListInvoices_FullMethodName  = "/invoices_service.InvoicesService/ListInvoices"
+	InvoicesService_CreateInvoice_FullMethodName         = "/invoices_service.InvoicesService/CreateInvoice"
+	InvoicesService_CheckInvoice_FullMethodName          = "/invoices_service.InvoicesService/CheckInvoice"
+	InvoicesService_UpdateInvoice_FullMethodName         = "/invoices_service.InvoicesService/UpdateInvoice"
+	InvoicesService_ListInvoices_FullMethodName          = "/invoices_service.InvoicesService/ListInvoices"
+	InvoicesService_CancelInvoice_FullMethodName         = "/invoices_service.InvoicesService/CancelInvoice"
+	InvoicesService_RefundInvoice_FullMethodName         = "/invoices_service.InvoicesService/RefundInvoice"
+	InvoicesService_RefreshQuote_FullMethodName          = "/invoices_service.InvoicesService/RefreshQuote"
+	InvoicesService_WatchInvoice_FullMethodName          = "/invoices_service.InvoicesService/WatchInvoice"
+	InvoicesService_SetWebhook_FullMethodName            = "/invoices_service.InvoicesService/SetWebhook"
+	InvoicesService_ListWebhookDeliveries_FullMethodName = "/invoices_service.InvoicesService/ListWebhookDeliveries"
+	InvoicesService_RedeliverWebhook_FullMethodName      = "/invoices_service.InvoicesService/RedeliverWebhook"
+	InvoicesService_GetInvoiceHistory_FullMethodName     = "/invoices_service.InvoicesService/GetInvoiceHistory"
+	InvoicesService_ListInvoicePayments_FullMethodName   = "/invoices_service.InvoicesService/ListInvoicePayments"
+	InvoicesService_ListPaymentMethods_FullMethodName    = "/invoices_service.InvoicesService/ListPaymentMethods"
 )
 
 // InvoicesServiceClient is the client API for InvoicesService service.
@@ -33,6 +43,23 @@ type InvoicesServiceClient interface {
 	CheckInvoice(ctx context.Context, in *CheckInvoiceRequest, opts ...grpc.CallOption) (*CheckInvoiceResponse, error)
 	UpdateInvoice(ctx context.Context, in *UpdateInvoiceRequest, opts ...grpc.CallOption) (*UpdateInvoiceResponse, error)
 	ListInvoices(ctx context.Context, in *ListInvoicesRequest, opts ...grpc.CallOption) (*ListInvoicesResponse, error)
+	CancelInvoice(ctx context.Context, in *CancelInvoiceRequest, opts ...grpc.CallOption) (*CancelInvoiceResponse, error)
+	RefundInvoice(ctx context.Context, in *RefundInvoiceRequest, opts ...grpc.CallOption) (*RefundInvoiceResponse, error)
+	RefreshQuote(ctx context.Context, in *RefreshQuoteRequest, opts ...grpc.CallOption) (*RefreshQuoteResponse, error)
+	// Streams invoice every time its status or received amount changes,
+	// stream is closed once invoice reaches final status
+	WatchInvoice(ctx context.Context, in *WatchInvoiceRequest, opts ...grpc.CallOption) (InvoicesService_WatchInvoiceClient, error)
+	// Sets URL invoice status changes of client are delivered to
+	SetWebhook(ctx context.Context, in *SetWebhookRequest, opts ...grpc.CallOption) (*SetWebhookResponse, error)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	// Schedules delivery to be sent again with reset attempts
+	RedeliverWebhook(ctx context.Context, in *RedeliverWebhookRequest, opts ...grpc.CallOption) (*RedeliverWebhookResponse, error)
+	// Returns status transitions of invoice in order they happened
+	GetInvoiceHistory(ctx context.Context, in *GetInvoiceHistoryRequest, opts ...grpc.CallOption) (*GetInvoiceHistoryResponse, error)
+	// Returns transactions payers sent to invoice address
+	ListInvoicePayments(ctx context.Context, in *ListInvoicePaymentsRequest, opts ...grpc.CallOption) (*ListInvoicePaymentsResponse, error)
+	// Returns chain and token pairs invoices can be paid with
+	ListPaymentMethods(ctx context.Context, in *ListPaymentMethodsRequest, opts ...grpc.CallOption) (*ListPaymentMethodsResponse, error)
 }
 
 type invoicesServiceClient struct {
@@ -79,6 +106,119 @@ func (c *invoicesServiceClient) ListInvoices(ctx context.Context, in *ListInvoic
 	return out, nil
 }
 
+func (c *invoicesServiceClient) CancelInvoice(ctx context.Context, in *CancelInvoiceRequest, opts ...grpc.CallOption) (*CancelInvoiceResponse, error) {
+	out := new(CancelInvoiceResponse)
+	err := c.cc.Invoke(ctx, InvoicesService_CancelInvoice_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesServiceClient) RefundInvoice(ctx context.Context, in *RefundInvoiceRequest, opts ...grpc.CallOption) (*RefundInvoiceResponse, error) {
+	out := new(RefundInvoiceResponse)
+	err := c.cc.Invoke(ctx, InvoicesService_RefundInvoice_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesServiceClient) RefreshQuote(ctx context.Context, in *RefreshQuoteRequest, opts ...grpc.CallOption) (*RefreshQuoteResponse, error) {
+	out := new(RefreshQuoteResponse)
+	err := c.cc.Invoke(ctx, InvoicesService_RefreshQuote_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesServiceClient) WatchInvoice(ctx context.Context, in *WatchInvoiceRequest, opts ...grpc.CallOption) (InvoicesService_WatchInvoiceClient, error) {
+	stream, err := c.cc.NewStream(ctx, &InvoicesService_ServiceDesc.Streams[0], InvoicesService_WatchInvoice_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &invoicesServiceWatchInvoiceClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type InvoicesService_WatchInvoiceClient interface {
+	Recv() (*WatchInvoiceResponse, error)
+	grpc.ClientStream
+}
+
+type invoicesServiceWatchInvoiceClient struct {
+	grpc.ClientStream
+}
+
+func (x *invoicesServiceWatchInvoiceClient) Recv() (*WatchInvoiceResponse, error) {
+	m := new(WatchInvoiceResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *invoicesServiceClient) SetWebhook(ctx context.Context, in *SetWebhookRequest, opts ...grpc.CallOption) (*SetWebhookResponse, error) {
+	out := new(SetWebhookResponse)
+	err := c.cc.Invoke(ctx, InvoicesService_SetWebhook_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesServiceClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, InvoicesService_ListWebhookDeliveries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesServiceClient) RedeliverWebhook(ctx context.Context, in *RedeliverWebhookRequest, opts ...grpc.CallOption) (*RedeliverWebhookResponse, error) {
+	out := new(RedeliverWebhookResponse)
+	err := c.cc.Invoke(ctx, InvoicesService_RedeliverWebhook_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesServiceClient) GetInvoiceHistory(ctx context.Context, in *GetInvoiceHistoryRequest, opts ...grpc.CallOption) (*GetInvoiceHistoryResponse, error) {
+	out := new(GetInvoiceHistoryResponse)
+	err := c.cc.Invoke(ctx, InvoicesService_GetInvoiceHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesServiceClient) ListInvoicePayments(ctx context.Context, in *ListInvoicePaymentsRequest, opts ...grpc.CallOption) (*ListInvoicePaymentsResponse, error) {
+	out := new(ListInvoicePaymentsResponse)
+	err := c.cc.Invoke(ctx, InvoicesService_ListInvoicePayments_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesServiceClient) ListPaymentMethods(ctx context.Context, in *ListPaymentMethodsRequest, opts ...grpc.CallOption) (*ListPaymentMethodsResponse, error) {
+	out := new(ListPaymentMethodsResponse)
+	err := c.cc.Invoke(ctx, InvoicesService_ListPaymentMethods_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InvoicesServiceServer is the server API for InvoicesService service.
 // All implementations must embed UnimplementedInvoicesServiceServer
 // for forward compatibility
@@ -87,6 +227,23 @@ type InvoicesServiceServer interface {
 	CheckInvoice(context.Context, *CheckInvoiceRequest) (*CheckInvoiceResponse, error)
 	UpdateInvoice(context.Context, *UpdateInvoiceRequest) (*UpdateInvoiceResponse, error)
 	ListInvoices(context.Context, *ListInvoicesRequest) (*ListInvoicesResponse, error)
+	CancelInvoice(context.Context, *CancelInvoiceRequest) (*CancelInvoiceResponse, error)
+	RefundInvoice(context.Context, *RefundInvoiceRequest) (*RefundInvoiceResponse, error)
+	RefreshQuote(context.Context, *RefreshQuoteRequest) (*RefreshQuoteResponse, error)
+	// Streams invoice every time its status or received amount changes,
+	// stream is closed once invoice reaches final status
+	WatchInvoice(*WatchInvoiceRequest, InvoicesService_WatchInvoiceServer) error
+	// Sets URL invoice status changes of client are delivered to
+	SetWebhook(context.Context, *SetWebhookRequest) (*SetWebhookResponse, error)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	// Schedules delivery to be sent again with reset attempts
+	RedeliverWebhook(context.Context, *RedeliverWebhookRequest) (*RedeliverWebhookResponse, error)
+	// Returns status transitions of invoice in order they happened
+	GetInvoiceHistory(context.Context, *GetInvoiceHistoryRequest) (*GetInvoiceHistoryResponse, error)
+	// Returns transactions payers sent to invoice address
+	ListInvoicePayments(context.Context, *ListInvoicePaymentsRequest) (*ListInvoicePaymentsResponse, error)
+	// Returns chain and token pairs invoices can be paid with
+	ListPaymentMethods(context.Context, *ListPaymentMethodsRequest) (*ListPaymentMethodsResponse, error)
 	mustEmbedUnimplementedInvoicesServiceServer()
 }
 
@@ -106,6 +263,36 @@ func (UnimplementedInvoicesServiceServer) UpdateInvoice(context.Context, *Update
 func (UnimplementedInvoicesServiceServer) ListInvoices(context.Context, *ListInvoicesRequest) (*ListInvoicesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListInvoices not implemented")
 }
+func (UnimplementedInvoicesServiceServer) CancelInvoice(context.Context, *CancelInvoiceRequest) (*CancelInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelInvoice not implemented")
+}
+func (UnimplementedInvoicesServiceServer) RefundInvoice(context.Context, *RefundInvoiceRequest) (*RefundInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefundInvoice not implemented")
+}
+func (UnimplementedInvoicesServiceServer) RefreshQuote(context.Context, *RefreshQuoteRequest) (*RefreshQuoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshQuote not implemented")
+}
+func (UnimplementedInvoicesServiceServer) WatchInvoice(*WatchInvoiceRequest, InvoicesService_WatchInvoiceServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchInvoice not implemented")
+}
+func (UnimplementedInvoicesServiceServer) SetWebhook(context.Context, *SetWebhookRequest) (*SetWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetWebhook not implemented")
+}
+func (UnimplementedInvoicesServiceServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedInvoicesServiceServer) RedeliverWebhook(context.Context, *RedeliverWebhookRequest) (*RedeliverWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RedeliverWebhook not implemented")
+}
+func (UnimplementedInvoicesServiceServer) GetInvoiceHistory(context.Context, *GetInvoiceHistoryRequest) (*GetInvoiceHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInvoiceHistory not implemented")
+}
+func (UnimplementedInvoicesServiceServer) ListInvoicePayments(context.Context, *ListInvoicePaymentsRequest) (*ListInvoicePaymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInvoicePayments not implemented")
+}
+func (UnimplementedInvoicesServiceServer) ListPaymentMethods(context.Context, *ListPaymentMethodsRequest) (*ListPaymentMethodsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPaymentMethods not implemented")
+}
 func (UnimplementedInvoicesServiceServer) mustEmbedUnimplementedInvoicesServiceServer() {}
 
 // UnsafeInvoicesServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -191,6 +378,189 @@ func _InvoicesService_ListInvoices_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _InvoicesService_CancelInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServiceServer).CancelInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoicesService_CancelInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServiceServer).CancelInvoice(ctx, req.(*CancelInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoicesService_RefundInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServiceServer).RefundInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoicesService_RefundInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServiceServer).RefundInvoice(ctx, req.(*RefundInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoicesService_RefreshQuote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshQuoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServiceServer).RefreshQuote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoicesService_RefreshQuote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServiceServer).RefreshQuote(ctx, req.(*RefreshQuoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoicesService_WatchInvoice_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchInvoiceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InvoicesServiceServer).WatchInvoice(m, &invoicesServiceWatchInvoiceServer{stream})
+}
+
+type InvoicesService_WatchInvoiceServer interface {
+	Send(*WatchInvoiceResponse) error
+	grpc.ServerStream
+}
+
+type invoicesServiceWatchInvoiceServer struct {
+	grpc.ServerStream
+}
+
+func (x *invoicesServiceWatchInvoiceServer) Send(m *WatchInvoiceResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _InvoicesService_SetWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServiceServer).SetWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoicesService_SetWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServiceServer).SetWebhook(ctx, req.(*SetWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoicesService_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServiceServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoicesService_ListWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServiceServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoicesService_RedeliverWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeliverWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServiceServer).RedeliverWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoicesService_RedeliverWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServiceServer).RedeliverWebhook(ctx, req.(*RedeliverWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoicesService_GetInvoiceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInvoiceHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServiceServer).GetInvoiceHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoicesService_GetInvoiceHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServiceServer).GetInvoiceHistory(ctx, req.(*GetInvoiceHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoicesService_ListInvoicePayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInvoicePaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServiceServer).ListInvoicePayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoicesService_ListInvoicePayments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServiceServer).ListInvoicePayments(ctx, req.(*ListInvoicePaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoicesService_ListPaymentMethods_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPaymentMethodsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServiceServer).ListPaymentMethods(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoicesService_ListPaymentMethods_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServiceServer).ListPaymentMethods(ctx, req.(*ListPaymentMethodsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // InvoicesService_ServiceDesc is the grpc.ServiceDesc for InvoicesService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -214,6 +584,253 @@ var InvoicesService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListInvoices",
 			Handler:    _InvoicesService_ListInvoices_Handler,
 		},
+		{
+			MethodName: "CancelInvoice",
+			Handler:    _InvoicesService_CancelInvoice_Handler,
+		},
+		{
+			MethodName: "RefundInvoice",
+			Handler:    _InvoicesService_RefundInvoice_Handler,
+		},
+		{
+			MethodName: "RefreshQuote",
+			Handler:    _InvoicesService_RefreshQuote_Handler,
+		},
+		{
+			MethodName: "SetWebhook",
+			Handler:    _InvoicesService_SetWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _InvoicesService_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "RedeliverWebhook",
+			Handler:    _InvoicesService_RedeliverWebhook_Handler,
+		},
+		{
+			MethodName: "GetInvoiceHistory",
+			Handler:    _InvoicesService_GetInvoiceHistory_Handler,
+		},
+		{
+			MethodName: "ListInvoicePayments",
+			Handler:    _InvoicesService_ListInvoicePayments_Handler,
+		},
+		{
+			MethodName: "ListPaymentMethods",
+			Handler:    _InvoicesService_ListPaymentMethods_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchInvoice",
+			Handler:       _InvoicesService_WatchInvoice_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/invoices-service/invoices-service.proto",
+}
+
+const (
+	InvoicesAdminService_RetryPayout_FullMethodName       = "/invoices_service.InvoicesAdminService/RetryPayout"
+	InvoicesAdminService_MarkResolved_FullMethodName      = "/invoices_service.InvoicesAdminService/MarkResolved"
+	InvoicesAdminService_ListStuckInvoices_FullMethodName = "/invoices_service.InvoicesAdminService/ListStuckInvoices"
+	InvoicesAdminService_ListAuditLog_FullMethodName      = "/invoices_service.InvoicesAdminService/ListAuditLog"
+)
+
+// InvoicesAdminServiceClient is the client API for InvoicesAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type InvoicesAdminServiceClient interface {
+	// Moves MANUAL_CONTROL invoice back to payout, optionally with gas overrides
+	RetryPayout(ctx context.Context, in *RetryPayoutRequest, opts ...grpc.CallOption) (*RetryPayoutResponse, error)
+	// Completes MANUAL_CONTROL invoice resolved outside of the service
+	MarkResolved(ctx context.Context, in *MarkResolvedRequest, opts ...grpc.CallOption) (*MarkResolvedResponse, error)
+	ListStuckInvoices(ctx context.Context, in *ListStuckInvoicesRequest, opts ...grpc.CallOption) (*ListStuckInvoicesResponse, error)
+	ListAuditLog(ctx context.Context, in *ListAuditLogRequest, opts ...grpc.CallOption) (*ListAuditLogResponse, error)
+}
+
+type invoicesAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInvoicesAdminServiceClient(cc grpc.ClientConnInterface) InvoicesAdminServiceClient {
+	return &invoicesAdminServiceClient{cc}
+}
+
+func (c *invoicesAdminServiceClient) RetryPayout(ctx context.Context, in *RetryPayoutRequest, opts ...grpc.CallOption) (*RetryPayoutResponse, error) {
+	out := new(RetryPayoutResponse)
+	err := c.cc.Invoke(ctx, InvoicesAdminService_RetryPayout_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesAdminServiceClient) MarkResolved(ctx context.Context, in *MarkResolvedRequest, opts ...grpc.CallOption) (*MarkResolvedResponse, error) {
+	out := new(MarkResolvedResponse)
+	err := c.cc.Invoke(ctx, InvoicesAdminService_MarkResolved_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesAdminServiceClient) ListStuckInvoices(ctx context.Context, in *ListStuckInvoicesRequest, opts ...grpc.CallOption) (*ListStuckInvoicesResponse, error) {
+	out := new(ListStuckInvoicesResponse)
+	err := c.cc.Invoke(ctx, InvoicesAdminService_ListStuckInvoices_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesAdminServiceClient) ListAuditLog(ctx context.Context, in *ListAuditLogRequest, opts ...grpc.CallOption) (*ListAuditLogResponse, error) {
+	out := new(ListAuditLogResponse)
+	err := c.cc.Invoke(ctx, InvoicesAdminService_ListAuditLog_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InvoicesAdminServiceServer is the server API for InvoicesAdminService service.
+// All implementations must embed UnimplementedInvoicesAdminServiceServer
+// for forward compatibility
+type InvoicesAdminServiceServer interface {
+	// Moves MANUAL_CONTROL invoice back to payout, optionally with gas overrides
+	RetryPayout(context.Context, *RetryPayoutRequest) (*RetryPayoutResponse, error)
+	// Completes MANUAL_CONTROL invoice resolved outside of the service
+	MarkResolved(context.Context, *MarkResolvedRequest) (*MarkResolvedResponse, error)
+	ListStuckInvoices(context.Context, *ListStuckInvoicesRequest) (*ListStuckInvoicesResponse, error)
+	ListAuditLog(context.Context, *ListAuditLogRequest) (*ListAuditLogResponse, error)
+	mustEmbedUnimplementedInvoicesAdminServiceServer()
+}
+
+// UnimplementedInvoicesAdminServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedInvoicesAdminServiceServer struct {
+}
+
+func (UnimplementedInvoicesAdminServiceServer) RetryPayout(context.Context, *RetryPayoutRequest) (*RetryPayoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetryPayout not implemented")
+}
+func (UnimplementedInvoicesAdminServiceServer) MarkResolved(context.Context, *MarkResolvedRequest) (*MarkResolvedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkResolved not implemented")
+}
+func (UnimplementedInvoicesAdminServiceServer) ListStuckInvoices(context.Context, *ListStuckInvoicesRequest) (*ListStuckInvoicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStuckInvoices not implemented")
+}
+func (UnimplementedInvoicesAdminServiceServer) ListAuditLog(context.Context, *ListAuditLogRequest) (*ListAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditLog not implemented")
+}
+func (UnimplementedInvoicesAdminServiceServer) mustEmbedUnimplementedInvoicesAdminServiceServer() {}
+
+// UnsafeInvoicesAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InvoicesAdminServiceServer will
+// result in compilation errors.
+type UnsafeInvoicesAdminServiceServer interface {
+	mustEmbedUnimplementedInvoicesAdminServiceServer()
+}
+
+func RegisterInvoicesAdminServiceServer(s grpc.ServiceRegistrar, srv InvoicesAdminServiceServer) {
+	s.RegisterService(&InvoicesAdminService_ServiceDesc, srv)
+}
+
+func _InvoicesAdminService_RetryPayout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetryPayoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesAdminServiceServer).RetryPayout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoicesAdminService_RetryPayout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesAdminServiceServer).RetryPayout(ctx, req.(*RetryPayoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoicesAdminService_MarkResolved_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkResolvedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesAdminServiceServer).MarkResolved(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoicesAdminService_MarkResolved_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesAdminServiceServer).MarkResolved(ctx, req.(*MarkResolvedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoicesAdminService_ListStuckInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStuckInvoicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesAdminServiceServer).ListStuckInvoices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoicesAdminService_ListStuckInvoices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesAdminServiceServer).ListStuckInvoices(ctx, req.(*ListStuckInvoicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoicesAdminService_ListAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesAdminServiceServer).ListAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InvoicesAdminService_ListAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesAdminServiceServer).ListAuditLog(ctx, req.(*ListAuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InvoicesAdminService_ServiceDesc is the grpc.ServiceDesc for InvoicesAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InvoicesAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "invoices_service.InvoicesAdminService",
+	HandlerType: (*InvoicesAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RetryPayout",
+			Handler:    _InvoicesAdminService_RetryPayout_Handler,
+		},
+		{
+			MethodName: "MarkResolved",
+			Handler:    _InvoicesAdminService_MarkResolved_Handler,
+		},
+		{
+			MethodName: "ListStuckInvoices",
+			Handler:    _InvoicesAdminService_ListStuckInvoices_Handler,
+		},
+		{
+			MethodName: "ListAuditLog",
+			Handler:    _InvoicesAdminService_ListAuditLog_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/invoices-service/invoices-service.proto",